@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/frontend"
+	gnarkio "github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+
+	"github.com/DelphinusLab/halo2aggregator-s/gnark/recursion"
+)
+
+// OuterBN254Circuit verifies, via recursion.PlonkVerifier, a single BN254
+// PLONK proof inside another BN254 circuit, so that proof's Solidity
+// verifier (emitted through the existing SolidityVerification path) checks
+// a much cheaper single pairing instead of the inner proof's own, possibly
+// batched, opening checks.
+type OuterBN254Circuit struct {
+	VerifyingKey  recursion.PlonkVerifyingKey `gnark:"-"`
+	Proof         recursion.PlonkProof
+	PublicWitness recursion.PlonkWitness `gnark:",public"`
+}
+
+func (c *OuterBN254Circuit) Define(api frontend.API) error {
+	u64Api, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	u256Api := NewU256API(api, u64Api)
+	bn254Api, err := NewBN254API(api, u256Api)
+	if err != nil {
+		return err
+	}
+
+	verifier := recursion.NewPlonkVerifier(api, bn254Api)
+	return verifier.AssertProof(c.VerifyingKey, c.Proof, c.PublicWitness)
+}
+
+var (
+	RecurseBN254Dir            = fmt.Sprintf("%s/recurse_bn254", DIR)
+	PlonkOuterBN254ProofPath   = fmt.Sprintf("%s/proof_outer", RecurseBN254Dir)
+	PlonkOuterBN254VerifierSol = fmt.Sprintf("%s/contract_plonk_outer.sol", RecurseBN254Dir)
+)
+
+// SetupBN254Recursion compiles the outer BN254 circuit wrapping a
+// verification of the given inner BN254 PLONK proof, runs setup and
+// proving, and writes pk_outer, vk_outer, proof_outer plus a Solidity
+// verifier for the outer proof via the existing SolidityVerification path.
+// Unlike SetupRecursion (BW6-761 over BLS12-377, via std/recursion/plonk),
+// this verifies the inner proof using non-native (emulated) arithmetic
+// entirely within the outer circuit's own curve, via recursion.PlonkVerifier.
+func SetupBN254Recursion(vk recursion.PlonkVerifyingKey, proof recursion.PlonkProof, publicWitness recursion.PlonkWitness) error {
+	if err := os.MkdirAll(RecurseBN254Dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	outerCircuit := &OuterBN254Circuit{
+		VerifyingKey:  recursion.PlaceholderVerifyingKey(vk),
+		Proof:         recursion.PlaceholderProof(proof),
+		PublicWitness: recursion.PlaceholderWitness(len(publicWitness.Public)),
+	}
+
+	outerCcs, err := Compile(outerCircuit, ecc.BN254, backend.PLONK, nil)
+	if err != nil {
+		return fmt.Errorf("compile outer circuit: %w", err)
+	}
+
+	outerPk, outerVk, err := LoadOrSetup(outerCcs, backend.PLONK, ecc.BN254)
+	if err != nil {
+		return fmt.Errorf("outer setup: %w", err)
+	}
+
+	witnessCircuit := &OuterBN254Circuit{
+		VerifyingKey:  vk,
+		Proof:         proof,
+		PublicWitness: publicWitness,
+	}
+
+	outerWitness, err := frontend.NewWitness(witnessCircuit, ecc.BN254.ScalarField())
+	if err != nil {
+		return fmt.Errorf("outer witness: %w", err)
+	}
+
+	outerProof, err := plonk.Prove(outerCcs, outerPk.(plonk.ProvingKey), outerWitness)
+	if err != nil {
+		return fmt.Errorf("outer prove: %w", err)
+	}
+
+	outerPublicWitness, err := outerWitness.Public()
+	if err != nil {
+		return err
+	}
+	if err := plonk.Verify(outerProof, outerVk.(plonk.VerifyingKey), outerPublicWitness); err != nil {
+		return fmt.Errorf("outer verify: %w", err)
+	}
+
+	fProof, err := os.Create(PlonkOuterBN254ProofPath)
+	if err != nil {
+		return err
+	}
+	if _, err := outerProof.(gnarkio.WriterRawTo).WriteRawTo(fProof); err != nil {
+		return err
+	}
+
+	// Exported directly rather than via SolidityVerification, which writes
+	// to the single shared PlonkVerifierPath: recurse.go's BW6-761 outer
+	// circuit follows the same pattern for the same reason, since an outer
+	// proof's verifier contract needs its own path alongside the inner
+	// proof's.
+	fSolidity, err := os.Create(PlonkOuterBN254VerifierSol)
+	if err != nil {
+		return err
+	}
+	defer fSolidity.Close()
+	if err := outerVk.(solidity.VerifyingKey).ExportSolidity(fSolidity); err != nil {
+		return fmt.Errorf("ExportSolidity: %w", err)
+	}
+
+	log.Printf("[recurseBN254] wrote %s, %s", PlonkOuterBN254ProofPath, PlonkOuterBN254VerifierSol)
+
+	return nil
+}
+
+// Bn254PlonkG1Data is a BN254 G1 point as decimal-string coordinates, the
+// JSON wire format consumed by loadBn254RecursionData.
+type Bn254PlonkG1Data [2]string
+
+// Bn254PlonkG2Data is a BN254 G2 point as decimal-string coordinates
+// (x.A0, x.A1, y.A0, y.A1), the same 4-string-per-point convention
+// Halo2VerifierConfig.VerifyCircuitG2Affine already uses.
+type Bn254PlonkG2Data [4]string
+
+// Bn254RecursionData is the JSON shape of bn254_recursion_proof.json: an
+// inner BN254 PLONK verifying key, proof and public witness, in the shape
+// recursion.PlonkVerifyingKey/PlonkProof/PlonkWitness expect.
+type Bn254RecursionData struct {
+	VerifyingKey struct {
+		Size           uint64           `json:"size"`
+		NbPublicInputs uint64           `json:"nb_public_inputs"`
+		Generator      string           `json:"generator"`
+		CosetShift1    string           `json:"coset_shift1"`
+		CosetShift2    string           `json:"coset_shift2"`
+		Ql             Bn254PlonkG1Data `json:"ql"`
+		Qr             Bn254PlonkG1Data `json:"qr"`
+		Qm             Bn254PlonkG1Data `json:"qm"`
+		Qo             Bn254PlonkG1Data `json:"qo"`
+		Qk             Bn254PlonkG1Data `json:"qk"`
+		S1             Bn254PlonkG1Data `json:"s1"`
+		S2             Bn254PlonkG1Data `json:"s2"`
+		S3             Bn254PlonkG1Data `json:"s3"`
+		KZGG2          Bn254PlonkG2Data `json:"kzg_g2"`
+		KZGG2X         Bn254PlonkG2Data `json:"kzg_g2_x"`
+	} `json:"verifying_key"`
+	Proof struct {
+		LRO          [3]Bn254PlonkG1Data `json:"lro"`
+		LROEvals     [3]string           `json:"lro_evals"`
+		PermEvals    [2]string           `json:"perm_evals"`
+		Z            Bn254PlonkG1Data    `json:"z"`
+		H            [3]Bn254PlonkG1Data `json:"h"`
+		BatchedProof struct {
+			H            Bn254PlonkG1Data `json:"h"`
+			ClaimedValue string           `json:"claimed_value"`
+		} `json:"batched_proof"`
+		ZShiftedOpening struct {
+			H            Bn254PlonkG1Data `json:"h"`
+			ClaimedValue string           `json:"claimed_value"`
+		} `json:"z_shifted_opening"`
+	} `json:"proof"`
+	PublicWitness []string `json:"public_witness"`
+}
+
+func parseDecimal(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal value %q", s)
+	}
+	return v, nil
+}
+
+func parseBn254PlonkG1(g Bn254PlonkG1Data) (recursion.PlonkG1, error) {
+	x, err := parseDecimal(g[0])
+	if err != nil {
+		return recursion.PlonkG1{}, err
+	}
+	y, err := parseDecimal(g[1])
+	if err != nil {
+		return recursion.PlonkG1{}, err
+	}
+	return recursion.PlonkG1{
+		X: emulated.ValueOf[emparams.BN254Fp](x),
+		Y: emulated.ValueOf[emparams.BN254Fp](y),
+	}, nil
+}
+
+func parseBn254PlonkG2(g Bn254PlonkG2Data) (sw_bn254.G2Affine, error) {
+	var point bn254.G2Affine
+	point.X.SetString(g[0], g[1])
+	point.Y.SetString(g[2], g[3])
+	if !point.IsOnCurve() {
+		return sw_bn254.G2Affine{}, fmt.Errorf("invalid g2 point")
+	}
+	return sw_bn254.NewG2Affine(point), nil
+}
+
+// loadBn254RecursionData reads path (bn254_recursion_proof.json) and
+// converts it into the recursion.PlonkVerifyingKey/PlonkProof/PlonkWitness
+// -recurseBN254 feeds to SetupBN254Recursion.
+func loadBn254RecursionData(path string) (recursion.PlonkVerifyingKey, recursion.PlonkProof, recursion.PlonkWitness, error) {
+	var data Bn254RecursionData
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+
+	generator, err := parseDecimal(data.VerifyingKey.Generator)
+	if err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	cosetShift1, err := parseDecimal(data.VerifyingKey.CosetShift1)
+	if err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	cosetShift2, err := parseDecimal(data.VerifyingKey.CosetShift2)
+	if err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+
+	var vk recursion.PlonkVerifyingKey
+	vk.Size = data.VerifyingKey.Size
+	vk.NbPublicInputs = data.VerifyingKey.NbPublicInputs
+	vk.Generator = generator
+	vk.CosetShift1 = cosetShift1
+	vk.CosetShift2 = cosetShift2
+	for _, f := range []struct {
+		data Bn254PlonkG1Data
+		dst  *recursion.PlonkG1
+	}{
+		{data.VerifyingKey.Ql, &vk.Ql}, {data.VerifyingKey.Qr, &vk.Qr}, {data.VerifyingKey.Qm, &vk.Qm},
+		{data.VerifyingKey.Qo, &vk.Qo}, {data.VerifyingKey.Qk, &vk.Qk},
+		{data.VerifyingKey.S1, &vk.S1}, {data.VerifyingKey.S2, &vk.S2}, {data.VerifyingKey.S3, &vk.S3},
+	} {
+		p, err := parseBn254PlonkG1(f.data)
+		if err != nil {
+			return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+		}
+		*f.dst = p
+	}
+	if vk.KZGG2, err = parseBn254PlonkG2(data.VerifyingKey.KZGG2); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	if vk.KZGG2X, err = parseBn254PlonkG2(data.VerifyingKey.KZGG2X); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+
+	var proof recursion.PlonkProof
+	for i := range data.Proof.LRO {
+		if proof.LRO[i], err = parseBn254PlonkG1(data.Proof.LRO[i]); err != nil {
+			return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+		}
+		if proof.H[i], err = parseBn254PlonkG1(data.Proof.H[i]); err != nil {
+			return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+		}
+		lroEval, err := parseDecimal(data.Proof.LROEvals[i])
+		if err != nil {
+			return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+		}
+		proof.LROEvals[i] = lroEval
+	}
+	for i := range data.Proof.PermEvals {
+		permEval, err := parseDecimal(data.Proof.PermEvals[i])
+		if err != nil {
+			return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+		}
+		proof.PermEvals[i] = permEval
+	}
+	if proof.Z, err = parseBn254PlonkG1(data.Proof.Z); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	if proof.BatchedProof.H, err = parseBn254PlonkG1(data.Proof.BatchedProof.H); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	if proof.BatchedProof.ClaimedValue, err = parseDecimal(data.Proof.BatchedProof.ClaimedValue); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	if proof.ZShiftedOpening.H, err = parseBn254PlonkG1(data.Proof.ZShiftedOpening.H); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+	if proof.ZShiftedOpening.ClaimedValue, err = parseDecimal(data.Proof.ZShiftedOpening.ClaimedValue); err != nil {
+		return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+	}
+
+	publicWitness := recursion.PlonkWitness{Public: make([]frontend.Variable, len(data.PublicWitness))}
+	for i, s := range data.PublicWitness {
+		w, err := parseDecimal(s)
+		if err != nil {
+			return recursion.PlonkVerifyingKey{}, recursion.PlonkProof{}, recursion.PlonkWitness{}, err
+		}
+		publicWitness.Public[i] = w
+	}
+
+	return vk, proof, publicWitness, nil
+}
@@ -1,9 +1,7 @@
 package main
 
 import (
-	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
 	"github.com/consensys/gnark/std/math/emulated/emparams"
 	"github.com/consensys/gnark/std/math/uints"
@@ -12,28 +10,115 @@ import (
 	"math/big"
 )
 
-type Halo2VerifierCircuit struct {
-	config     Halo2VerifierConfig
+// Halo2VerifierProof holds the public instance and the prover transcript
+// for a single Halo2 proof to be verified inside Halo2VerifierCircuit.
+type Halo2VerifierProof struct {
 	Instance   [][]frontend.Variable `gnark:",public"`
 	Transcript []frontend.Variable
 }
 
+type Halo2VerifierCircuit struct {
+	config Halo2VerifierConfig
+	Proofs []Halo2VerifierProof
+}
+
 type Halo2VerifierAPI struct {
-	config   Halo2VerifierConfig
-	api      frontend.API
-	u64Api   *uints.BinaryField[uints.U64]
-	u256Api  *U256API
-	bn254Api *BN254API
+	config  Halo2VerifierConfig
+	api     frontend.API
+	u64Api  *uints.BinaryField[uints.U64]
+	u256Api *U256API
+	// curve abstracts the pairing-friendly curve the Halo2 proof itself was
+	// produced over (see ProofCurve in Halo2VerifierConfig), so the
+	// transcript and final pairing check work the same regardless of which
+	// curve is configured.
+	curve Halo2Curve
+	// instanceBasisBN254 caches VerifyCircuitGLagrange as constant BN254
+	// curve points, so calcInstanceCommitment can fold it with a single MSM
+	// instead of a sequential ScalarMulConstant+Add loop. It is only used
+	// when curve is BN254Curve; other curves fall back to instanceBasisXY.
+	instanceBasisBN254 []*sw_emulated.AffinePoint[emparams.BN254Fp]
+	// instanceBasisXY holds VerifyCircuitGLagrange as raw (x, y) pairs,
+	// parsed once at circuit-compile time, for curves that don't have a
+	// dedicated MSM fast path.
+	instanceBasisXY [][2]big.Int
+	// omega is config.Omega parsed once at circuit-compile time, used by
+	// verify to derive the shifted evaluation point zeta*omega.
+	omega *big.Int
+}
+
+func NewHalo2VerifierAPI(config Halo2VerifierConfig, api frontend.API, u64Api *uints.BinaryField[uints.U64], u256Api *U256API, bn254Api *BN254API) (Halo2VerifierAPI, error) {
+	curve, err := newHalo2Curve(api, u64Api, bn254Api, config)
+	if err != nil {
+		return Halo2VerifierAPI{}, err
+	}
+
+	instanceBasisXY, err := parseInstanceBasis(config)
+	if err != nil {
+		return Halo2VerifierAPI{}, err
+	}
+
+	omega := new(big.Int)
+	if config.Omega != "" {
+		if _, succeed := omega.SetString(config.Omega, 10); !succeed {
+			return Halo2VerifierAPI{}, fmt.Errorf("invalid Omega %q", config.Omega)
+		}
+	} else if config.NbShiftedEvals != 0 {
+		return Halo2VerifierAPI{}, fmt.Errorf("NbShiftedEvals %d requires Omega to be set", config.NbShiftedEvals)
+	}
+
+	halo2Api := Halo2VerifierAPI{
+		config:          config,
+		api:             api,
+		u64Api:          u64Api,
+		u256Api:         u256Api,
+		curve:           curve,
+		instanceBasisXY: instanceBasisXY,
+		omega:           omega,
+	}
+
+	if _, ok := curve.(*BN254Curve); ok {
+		halo2Api.instanceBasisBN254, err = buildInstanceBasisBN254(bn254Api, instanceBasisXY)
+		if err != nil {
+			return Halo2VerifierAPI{}, err
+		}
+	}
+
+	return halo2Api, nil
+}
+
+func parseInstanceBasis(config Halo2VerifierConfig) ([][2]big.Int, error) {
+	basis := make([][2]big.Int, len(config.VerifyCircuitGLagrange))
+
+	for i := range config.VerifyCircuitGLagrange {
+		x, succeed := new(big.Int).SetString(config.VerifyCircuitGLagrange[i][0], 10)
+		if !succeed {
+			return nil, fmt.Errorf("invalid x in VerifyCircuitGLagrange at %d, with value %s", i, config.VerifyCircuitGLagrange[i][0])
+		}
+		y, succeed := new(big.Int).SetString(config.VerifyCircuitGLagrange[i][1], 10)
+		if !succeed {
+			return nil, fmt.Errorf("invalid y in VerifyCircuitGLagrange at %d, with value %s", i, config.VerifyCircuitGLagrange[i][1])
+		}
+
+		basis[i] = [2]big.Int{*x, *y}
+	}
+
+	return basis, nil
 }
 
-func NewHalo2VerifierAPI(config Halo2VerifierConfig, api frontend.API, u64Api *uints.BinaryField[uints.U64], u256Api *U256API, bn254Api *BN254API) Halo2VerifierAPI {
-	return Halo2VerifierAPI{
-		config:   config,
-		api:      api,
-		u64Api:   u64Api,
-		u256Api:  u256Api,
-		bn254Api: bn254Api,
+func buildInstanceBasisBN254(bn254Api *BN254API, basisXY [][2]big.Int) ([]*sw_emulated.AffinePoint[emparams.BN254Fp], error) {
+	basis := make([]*sw_emulated.AffinePoint[emparams.BN254Fp], len(basisXY))
+	for i := range basisXY {
+		basis[i] = bn254Api.BN254FromConstant(basisXY[i])
 	}
+	return basis, nil
+}
+
+// newTranscript returns a fresh Fiat-Shamir transcript. Each proof verified
+// against this API (see BatchSize in Halo2VerifierConfig) gets its own
+// transcript instance so that absorbing one proof's data cannot leak into
+// another proof's challenge derivation.
+func (halo2Api *Halo2VerifierAPI) newTranscript() (fiatShamirTranscript, error) {
+	return newFiatShamirTranscript(halo2Api.api, halo2Api.u64Api, halo2Api.u256Api, halo2Api.config)
 }
 
 func ScalarPow(api frontend.API, x frontend.Variable, n uint) frontend.Variable {
@@ -51,76 +136,272 @@ func ScalarPow(api frontend.API, x frontend.Variable, n uint) frontend.Variable
 	return acc
 }
 
-func (halo2Api *Halo2VerifierAPI) calcSingleInstanceCommitment(index int, instance frontend.Variable) (*sw_emulated.AffinePoint[emparams.BN254Fp], error) {
-	x, succeed := new(big.Int).SetString(halo2Api.config.VerifyCircuitGLagrange[index][0], 10)
-	if !succeed {
-		return nil, fmt.Errorf("invalid x in VerifyCircuitGLagrange at %d, with value %s", index, halo2Api.config.VerifyCircuitGLagrange[index][0])
+// calcInstanceCommitment commits to instances against the fixed Lagrange
+// basis VerifyCircuitGLagrange. For BN254 proofs it folds the basis with a
+// single MultiScalarMul, the dominant cost for instance columns with many
+// public inputs; other curves fall back to a ScalarMulConstant+Add loop via
+// the Halo2Curve interface.
+func (halo2Api *Halo2VerifierAPI) calcInstanceCommitment(instances []frontend.Variable) (any, error) {
+	if len(instances) > len(halo2Api.instanceBasisXY) {
+		return nil, fmt.Errorf("calcInstanceCommitment: %d instances exceed basis size %d", len(instances), len(halo2Api.instanceBasisXY))
 	}
-	y, succeed := new(big.Int).SetString(halo2Api.config.VerifyCircuitGLagrange[index][1], 10)
-	if !succeed {
-		return nil, fmt.Errorf("invalid y in VerifyCircuitGLagrange at %d, with value %s", index, halo2Api.config.VerifyCircuitGLagrange[index][1])
+
+	if bn254Curve, ok := halo2Api.curve.(*BN254Curve); ok {
+		return bn254Curve.bn254Api.BN254MultiScalarMul(halo2Api.instanceBasisBN254[:len(instances)], instances)
 	}
 
-	return halo2Api.bn254Api.BN254ScalarMulConstant([2]big.Int{*x, *y}, instance), nil
+	var acc any
+	for i := range instances {
+		term := halo2Api.curve.ScalarMulConstant(halo2Api.instanceBasisXY[i], instances[i])
+		if acc == nil {
+			acc = term
+		} else {
+			acc = halo2Api.curve.AddG1(acc, term)
+		}
+	}
+
+	return acc, nil
+}
+
+func (halo2Api *Halo2VerifierAPI) proofToWords(proof []frontend.Variable) ([]any, error) {
+	return halo2Api.curve.ParseProofWords(halo2Api.u64Api, proof)
 }
 
-func (halo2Api *Halo2VerifierAPI) calcInstanceCommitment(instances []frontend.Variable) (*sw_emulated.AffinePoint[emparams.BN254Fp], error) {
-	acc, err := halo2Api.calcSingleInstanceCommitment(0, instances[0])
+// pedersenTerms holds the extra (G1, G2) pairing terms contributed by a
+// proof's committed (Pedersen/BSB22-style) columns, already weighted by
+// verifyPedersenKnowledge's Fiat-Shamir scalar and ready to be folded into
+// the circuit's single final PairingCheck call alongside its SHPlonk pair.
+type pedersenTerms struct {
+	commitments []any
+	poks        []any
+	negSigmaG2s []any
+	g2s         []any
+}
+
+// verifyOneProof runs the per-proof SHPlonk verification steps and returns
+// its pairing pair (p1, p2), without performing the final pairing check.
+func (halo2Api *Halo2VerifierAPI) verifyOneProof(proof *Halo2VerifierProof) (any, any, pedersenTerms, error) {
+	transcript, err := halo2Api.proofToWords(proof.Transcript)
 	if err != nil {
-		return nil, err
+		return nil, nil, pedersenTerms{}, err
 	}
 
-	for i := 1; i < len(instances); i++ {
-		p, err := halo2Api.calcSingleInstanceCommitment(i, instances[i])
+	instanceCommitments := make([]any, len(proof.Instance))
+	for i := range proof.Instance {
+		instanceCommitments[i], err = halo2Api.calcInstanceCommitment(proof.Instance[i])
 		if err != nil {
-			return nil, err
+			return nil, nil, pedersenTerms{}, err
 		}
+	}
 
-		acc = halo2Api.bn254Api.BN254AddG1(acc, p)
+	challenges, commitments, evals, pedersenCommitments, pedersenPoks, err := halo2Api.getChallengesShPlonkCircuit(instanceCommitments, transcript)
+	if err != nil {
+		return nil, nil, pedersenTerms{}, err
 	}
-	return acc, nil
+
+	pt, err := halo2Api.verifyPedersenKnowledge(pedersenCommitments, pedersenPoks)
+	if err != nil {
+		return nil, nil, pedersenTerms{}, err
+	}
+
+	p1, p2, err := halo2Api.verify(commitments, evals, challenges)
+	if err != nil {
+		return nil, nil, pedersenTerms{}, err
+	}
+	return p1, p2, pt, nil
 }
 
-func (halo2Api *Halo2VerifierAPI) GetVerifyCircuitsG2Affine() []sw_bn254.G2Affine {
-	res := make([]sw_bn254.G2Affine, 2)
-
-	for i := 0; i < 2; i++ {
-		g := bn254.G2Affine{}
-		g.X.SetString(
-			halo2Api.config.VerifyCircuitG2Affine[i][0],
-			halo2Api.config.VerifyCircuitG2Affine[i][1],
-		)
-		g.Y.SetString(
-			halo2Api.config.VerifyCircuitG2Affine[i][2],
-			halo2Api.config.VerifyCircuitG2Affine[i][3],
-		)
-		if !g.IsOnCurve() {
-			panic("invalid g2")
-		}
-		res[i] = sw_bn254.NewG2Affine(g)
+// verify performs the final SHPlonk/KZG batched-opening pairing-pair
+// computation. getChallengesShPlonkCircuit's opSeq lays `commitments` out as
+// every opened polynomial commitment followed by the two opening-proof
+// commitments (the main multiopen quotient W, then the shifted-rotation
+// quotient W'); `evals` holds the claimed opening of each of those opened
+// commitments at zeta, followed by a second, shorter run of NbShiftedEvals
+// claimed openings at zeta*Omega for the NbShiftedEvals opened commitments
+// starting right after the advices and lookup-m commitments (group 3 of
+// opSeq: the permutation/lookup grand-product polynomials, which need both
+// their current-row and next-row openings checked). This mirrors
+// recursion.PlonkVerifier.AssertProof's split between shiftedCommitment (zeta) and
+// zShiftedCommitment (zeta*omega): each group is folded against its own
+// point with powers of the shared multiopen challenge v, then the two
+// folded checks are combined via the multiopen challenge u so a single
+// pairing pair still suffices:
+//
+//	Fzeta = Σ v^i * opened[i],       Ezeta = Σ v^i * evals[i]
+//	Fshift = Σ v^(base+i) * shifted[i], Eshift = Σ v^(base+i) * shiftedEvals[i]
+//	p1 = (Fzeta - Ezeta*G1 + zeta*W) + u*(Fshift - Eshift*G1 + zeta*Omega*W')
+//	p2 = -(W + u*W')
+//
+// so that e(p1, [1]) * e(p2, [x]) == 1 iff every opening, at its own point,
+// holds. When NbShiftedEvals is 0, this degenerates to a single zeta-only
+// check with p2 = -W, leaving proofs with no shifted openings unaffected.
+func (halo2Api *Halo2VerifierAPI) verify(
+	commitments []any,
+	evals []frontend.Variable,
+	challenges []frontend.Variable,
+) (any, any, error) {
+	if len(challenges) < 8 {
+		return nil, nil, fmt.Errorf("verify: expected at least 8 challenges, got %d", len(challenges))
 	}
+	if len(commitments) < 2 {
+		return nil, nil, fmt.Errorf("verify: expected at least 2 commitments (the two opening proofs), got %d", len(commitments))
+	}
+	opened := commitments[:len(commitments)-2]
+	nbShifted := int(halo2Api.config.NbShiftedEvals)
+	// The shifted-opening group is group 3 of opSeq (the permutation/lookup
+	// grand-product commitments), not the tail of `opened`: group 4 (the
+	// Degree quotient-h commitments) is always appended after it, so slicing
+	// from the end would pick up quotient-h commitments instead.
+	shiftedBase := int(halo2Api.config.NbAdvices + halo2Api.config.NbLookupsM)
+	if shiftedBase+nbShifted > len(opened) {
+		return nil, nil, fmt.Errorf("verify: shifted-opening group [%d:%d) exceeds %d opened commitments", shiftedBase, shiftedBase+nbShifted, len(opened))
+	}
+	if len(evals) != len(opened)+nbShifted {
+		return nil, nil, fmt.Errorf("verify: expected %d evals (one per opened commitment, plus %d shifted-point evals), got %d", len(opened), nbShifted, len(evals))
+	}
+	zetaEvals, shiftedEvals := evals[:len(opened)], evals[len(opened):]
+	shifted := opened[shiftedBase : shiftedBase+nbShifted]
+
+	zeta := challenges[4]
+	v := challenges[6]
+	u := challenges[7]
+
+	w, wShifted := commitments[len(commitments)-2], commitments[len(commitments)-1]
+
+	F := opened[0]
+	E := zetaEvals[0]
+	for i := 1; i < len(opened); i++ {
+		vPow := ScalarPow(halo2Api.api, v, uint(i))
+		F = halo2Api.curve.ScalarMulAndAddG1(opened[i], vPow, F)
+		E = halo2Api.api.Add(E, halo2Api.api.Mul(vPow, zetaEvals[i]))
+	}
+	negEG1 := halo2Api.curve.ScalarMulConstant(halo2Api.curve.G1Gen(), halo2Api.api.Neg(E))
+	zetaFolded := halo2Api.curve.AddG1(F, negEG1)
+	zetaFolded = halo2Api.curve.AddG1(zetaFolded, halo2Api.curve.ScalarMul(w, zeta))
 
-	return res
+	if nbShifted == 0 {
+		return zetaFolded, halo2Api.curve.Neg(w), nil
+	}
+
+	vBase := ScalarPow(halo2Api.api, v, uint(shiftedBase))
+	Fs := halo2Api.curve.ScalarMul(shifted[0], vBase)
+	Es := halo2Api.api.Mul(vBase, shiftedEvals[0])
+	for i := 1; i < nbShifted; i++ {
+		vPow := ScalarPow(halo2Api.api, v, uint(shiftedBase+i))
+		Fs = halo2Api.curve.ScalarMulAndAddG1(shifted[i], vPow, Fs)
+		Es = halo2Api.api.Add(Es, halo2Api.api.Mul(vPow, shiftedEvals[i]))
+	}
+	negEsG1 := halo2Api.curve.ScalarMulConstant(halo2Api.curve.G1Gen(), halo2Api.api.Neg(Es))
+	shiftedFolded := halo2Api.curve.AddG1(Fs, negEsG1)
+	zetaOmega := halo2Api.api.Mul(zeta, halo2Api.omega)
+	shiftedFolded = halo2Api.curve.AddG1(shiftedFolded, halo2Api.curve.ScalarMul(wShifted, zetaOmega))
+
+	p1 := halo2Api.curve.AddG1(zetaFolded, halo2Api.curve.ScalarMul(shiftedFolded, u))
+	q := halo2Api.curve.ScalarMulAndAddG1(wShifted, u, w)
+	p2 := halo2Api.curve.Neg(q)
+
+	return p1, p2, nil
 }
 
-func (halo2Api *Halo2VerifierAPI) proofToU256(proof []frontend.Variable) ([]U256, error) {
-	if len(proof)%32 != 0 {
-		return nil, fmt.Errorf("invalid proof size")
+// verifyPedersenKnowledge weighs each committed column's (commitment, pok)
+// pair by a fresh Fiat-Shamir scalar rho (rho^i for column i), so the N
+// per-column knowledge-of-opening checks can all be folded into the
+// circuit's single final PairingCheck call instead of needing N separate
+// pairing checks. Unlike batchPairingPairs, the weighted points are kept
+// as N separate pairs rather than summed together, since each column
+// pairs against its own G2 point (PedersenGRootSigmaNeg / PedersenG2).
+func (halo2Api *Halo2VerifierAPI) verifyPedersenKnowledge(commitments, poks []any) (pedersenTerms, error) {
+	if len(commitments) == 0 {
+		return pedersenTerms{}, nil
 	}
 
-	transcript := make([]U256, len(proof)/32)
-	for i := range transcript {
-		for j := 0; j < 4; j++ {
-			for k := 0; k < 8; k++ {
-				transcript[i][j][k] = halo2Api.u64Api.ByteValueOf(proof[i*32+j*8+k])
-			}
-		}
+	negSigmaG2s, err := halo2Api.curve.ParseG2Points(halo2Api.config.PedersenGRootSigmaNeg)
+	if err != nil {
+		return pedersenTerms{}, fmt.Errorf("verifyPedersenKnowledge: %w", err)
+	}
+	g2s, err := halo2Api.curve.ParseG2Points(halo2Api.config.PedersenG2)
+	if err != nil {
+		return pedersenTerms{}, fmt.Errorf("verifyPedersenKnowledge: %w", err)
+	}
+	if len(negSigmaG2s) != len(commitments) || len(g2s) != len(commitments) {
+		return pedersenTerms{}, fmt.Errorf("verifyPedersenKnowledge: pedersen config size mismatch: %d commitments, %d PedersenGRootSigmaNeg, %d PedersenG2", len(commitments), len(negSigmaG2s), len(g2s))
+	}
+
+	t, err := halo2Api.newTranscript()
+	if err != nil {
+		return pedersenTerms{}, err
+	}
+
+	for i := range commitments {
+		cx, cy := halo2Api.curve.PointXY(commitments[i])
+		px, py := halo2Api.curve.PointXY(poks[i])
+		t.commonWord(halo2Api.curve, cx)
+		t.commonWord(halo2Api.curve, cy)
+		t.commonWord(halo2Api.curve, px)
+		t.commonWord(halo2Api.curve, py)
+	}
+
+	rho, err := t.squeezeChallenge()
+	if err != nil {
+		return pedersenTerms{}, err
 	}
 
-	return transcript, nil
+	scaledCommitments := make([]any, len(commitments))
+	scaledPoks := make([]any, len(poks))
+	for i := range commitments {
+		rhoPow := ScalarPow(halo2Api.api, rho, uint(i))
+		scaledCommitments[i] = halo2Api.curve.ScalarMul(commitments[i], rhoPow)
+		scaledPoks[i] = halo2Api.curve.ScalarMul(poks[i], rhoPow)
+	}
+
+	return pedersenTerms{
+		commitments: scaledCommitments,
+		poks:        scaledPoks,
+		negSigmaG2s: negSigmaG2s,
+		g2s:         g2s,
+	}, nil
+}
+
+// batchPairingPairs collapses the per-proof pairing pairs of a batch into a
+// single pair via a random linear combination: it draws one Fiat-Shamir
+// scalar rho by hashing every (p1_i, p2_i) together, then returns
+// P1 = Σ rho^i·p1_i, P2 = Σ rho^i·p2_i, so that the whole batch can be
+// checked with a single PairingCheck call.
+func (halo2Api *Halo2VerifierAPI) batchPairingPairs(p1s, p2s []any) (any, any, error) {
+	rhoTranscript, err := halo2Api.newTranscript()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range p1s {
+		x1, y1 := halo2Api.curve.PointXY(p1s[i])
+		x2, y2 := halo2Api.curve.PointXY(p2s[i])
+		rhoTranscript.commonWord(halo2Api.curve, x1)
+		rhoTranscript.commonWord(halo2Api.curve, y1)
+		rhoTranscript.commonWord(halo2Api.curve, x2)
+		rhoTranscript.commonWord(halo2Api.curve, y2)
+	}
+
+	rho, err := rhoTranscript.squeezeChallenge()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	P1, P2 := p1s[0], p2s[0]
+	for i := 1; i < len(p1s); i++ {
+		rhoPow := ScalarPow(halo2Api.api, rho, uint(i))
+		P1 = halo2Api.curve.ScalarMulAndAddG1(p1s[i], rhoPow, P1)
+		P2 = halo2Api.curve.ScalarMulAndAddG1(p2s[i], rhoPow, P2)
+	}
+
+	return P1, P2, nil
 }
 
 func (circuit *Halo2VerifierCircuit) Define(api frontend.API) error {
+	if len(circuit.Proofs) == 0 {
+		return fmt.Errorf("Halo2VerifierCircuit: no proofs to verify")
+	}
+
 	u64Api, err := uints.New[uints.U64](api)
 	if err != nil {
 		return err
@@ -133,42 +414,48 @@ func (circuit *Halo2VerifierCircuit) Define(api frontend.API) error {
 		return err
 	}
 
-	halo2Api := NewHalo2VerifierAPI(circuit.config, api, u64Api, u256Api, bn254Api)
-
-	transcript, err := halo2Api.proofToU256(circuit.Transcript)
+	halo2Api, err := NewHalo2VerifierAPI(circuit.config, api, u64Api, u256Api, bn254Api)
 	if err != nil {
 		return err
 	}
 
-	instanceCommitments := make([]*sw_emulated.AffinePoint[emparams.BN254Fp], len(circuit.Instance))
+	p1s := make([]any, len(circuit.Proofs))
+	p2s := make([]any, len(circuit.Proofs))
 
-	for i := range circuit.Instance {
-		instanceCommitments[i], err = halo2Api.calcInstanceCommitment(circuit.Instance[i])
+	var pedersenCommitments, pedersenPoks, pedersenNegSigmaG2s, pedersenG2s []any
+	for i := range circuit.Proofs {
+		var pt pedersenTerms
+		p1s[i], p2s[i], pt, err = halo2Api.verifyOneProof(&circuit.Proofs[i])
 		if err != nil {
 			return err
 		}
+		pedersenCommitments = append(pedersenCommitments, pt.commitments...)
+		pedersenPoks = append(pedersenPoks, pt.poks...)
+		pedersenNegSigmaG2s = append(pedersenNegSigmaG2s, pt.negSigmaG2s...)
+		pedersenG2s = append(pedersenG2s, pt.g2s...)
 	}
 
-	challenges, commitments, evals, err := halo2Api.getChallengesShPlonkCircuit(instanceCommitments, transcript)
-	if err != nil {
-		return err
+	p1, p2 := p1s[0], p2s[0]
+	if len(circuit.Proofs) > 1 {
+		p1, p2, err = halo2Api.batchPairingPairs(p1s, p2s)
+		if err != nil {
+			return err
+		}
 	}
 
-	p1, p2 := halo2Api.verify(instanceCommitments, commitments, evals, challenges)
-	g2Points := halo2Api.GetVerifyCircuitsG2Affine()
-
-	// Do pairing
-	pairingApi, err := sw_bn254.NewPairing(api)
+	g2Points, err := halo2Api.curve.G2Affines(circuit.config)
 	if err != nil {
-		return fmt.Errorf("NewPairing: %w", err)
+		return err
 	}
 
-	err = pairingApi.PairingCheck(
-		[]*sw_emulated.AffinePoint[emparams.BN254Fp]{p1, p2},
-		[]*sw_bn254.G2Affine{&g2Points[0], &g2Points[1]},
-	)
+	// Fold the SHPlonk pair and every committed column's weighted
+	// knowledge-of-opening pair into one multi-pairing check:
+	// e(p1,g2[0])·e(p2,g2[1])·Πe(commitment_i,negSigmaG2_i)·e(pok_i,g2_i) == 1.
+	allP1s := append([]any{p1}, pedersenCommitments...)
+	allP2s := append([]any{p2}, pedersenPoks...)
+	allG2s := append(append([]any{g2Points[0]}, pedersenNegSigmaG2s...), append([]any{g2Points[1]}, pedersenG2s...)...)
 
-	if err != nil {
+	if err := halo2Api.curve.PairingCheck(allP1s, allP2s, allG2s); err != nil {
 		return fmt.Errorf("pair: %w", err)
 	}
 
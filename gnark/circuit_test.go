@@ -0,0 +1,209 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// fakeScalarCurve implements Halo2Curve by treating every "point" as a bare
+// frontend.Variable holding the exponent of a fixed (unmodeled) generator,
+// so Halo2VerifierAPI.verify's folding arithmetic can be exercised as plain
+// field arithmetic without paying for real elliptic-curve operations. Only
+// the methods verify actually calls are meaningful; the rest are unused by
+// this test and just panic if ever reached.
+type fakeScalarCurve struct{ api frontend.API }
+
+func (c *fakeScalarCurve) ScalarMulConstant(_ [2]big.Int, scalar frontend.Variable) any {
+	return scalar
+}
+func (c *fakeScalarCurve) ScalarMul(point any, scalar frontend.Variable) any {
+	return c.api.Mul(scalar, point.(frontend.Variable))
+}
+func (c *fakeScalarCurve) AddG1(a, b any) any {
+	return c.api.Add(a.(frontend.Variable), b.(frontend.Variable))
+}
+func (c *fakeScalarCurve) ScalarMulAndAddG1(point any, scalar frontend.Variable, b any) any {
+	return c.api.Add(c.api.Mul(scalar, point.(frontend.Variable)), b.(frontend.Variable))
+}
+func (c *fakeScalarCurve) Neg(point any) any { return c.api.Neg(point.(frontend.Variable)) }
+func (c *fakeScalarCurve) G1Gen() [2]big.Int { return [2]big.Int{} }
+func (c *fakeScalarCurve) ParseProofWords(_ *uints.BinaryField[uints.U64], _ []frontend.Variable) ([]any, error) {
+	panic("not used by verify")
+}
+func (c *fakeScalarCurve) AssertOnCurve(_, _ any) any { panic("not used by verify") }
+func (c *fakeScalarCurve) PointXY(_ any) (any, any)   { panic("not used by verify") }
+func (c *fakeScalarCurve) WordBytesBE(_ any) []uints.U8 {
+	panic("not used by verify")
+}
+func (c *fakeScalarCurve) WordToValue(_ any) frontend.Variable {
+	panic("not used by verify")
+}
+func (c *fakeScalarCurve) G2Affines(_ Halo2VerifierConfig) ([]any, error) {
+	panic("not used by verify")
+}
+func (c *fakeScalarCurve) ParseG2Points(_ [][]string) ([]any, error) {
+	panic("not used by verify")
+}
+func (c *fakeScalarCurve) PairingCheck(_, _, _ []any) error { panic("not used by verify") }
+
+// verifyShiftedGroupCircuit exercises Halo2VerifierAPI.verify's shifted-
+// opening group selection: with NbAdvices=1, NbLookupsM=1, no permutation
+// groups or lookup-Zs and Degree=1, the opened-commitment list is
+// [advice, lookupM, z, quotientH], so the NbShiftedEvals=1 shifted group
+// must be z (opened[2:3], right after the advice/lookupM commitments), not
+// quotientH (opened[len(opened)-1:], the tail group 4 always contributes).
+type verifyShiftedGroupCircuit struct {
+	Advice, LookupM, Z, QuotientH frontend.Variable
+	W, WShifted                   frontend.Variable
+	EvalAdvice, EvalLookupM       frontend.Variable
+	EvalZ, EvalQuotientH          frontend.Variable
+	ShiftedEvalZ                  frontend.Variable
+	Zeta, V, U                    frontend.Variable
+	ExpectedP1, ExpectedP2        frontend.Variable
+}
+
+func (c *verifyShiftedGroupCircuit) Define(api frontend.API) error {
+	halo2Api := Halo2VerifierAPI{
+		config: Halo2VerifierConfig{
+			NbAdvices:           1,
+			NbLookupsM:          1,
+			NbPermutationGroups: 0,
+			NbLookupsZs:         0,
+			Degree:              1,
+			NbShiftedEvals:      1,
+		},
+		api:   api,
+		curve: &fakeScalarCurve{api: api},
+		omega: big.NewInt(1000),
+	}
+
+	commitments := []any{c.Advice, c.LookupM, c.Z, c.QuotientH, c.W, c.WShifted}
+	evals := []frontend.Variable{c.EvalAdvice, c.EvalLookupM, c.EvalZ, c.EvalQuotientH, c.ShiftedEvalZ}
+	challenges := []frontend.Variable{0, 0, 0, 0, c.Zeta, 0, c.V, c.U}
+
+	p1, p2, err := halo2Api.verify(commitments, evals, challenges)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(p1.(frontend.Variable), c.ExpectedP1)
+	api.AssertIsEqual(p2.(frontend.Variable), c.ExpectedP2)
+	return nil
+}
+
+func TestVerifyUsesGroup3AsShiftedOpening(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	valid := &verifyShiftedGroupCircuit{
+		Advice: 2, LookupM: 3, Z: 5, QuotientH: 7,
+		W: 11, WShifted: 13,
+		EvalAdvice: 21, EvalLookupM: 22, EvalZ: 23, EvalQuotientH: 24,
+		ShiftedEvalZ: 25,
+		Zeta:         100, V: 9, U: 17,
+		// p1 = (F - E + zeta*w) + u*(Fs - Es + zeta*omega*ws), folding at
+		// zeta over [advice, lookupM, z, quotientH] and at zeta*omega over
+		// [z] alone (shiftedBase = NbAdvices+NbLookupsM = 2).
+		ExpectedP1: 22059519,
+		// p2 = -(u*ws + w)
+		ExpectedP2: -232,
+	}
+
+	assert.CheckCircuit(&verifyShiftedGroupCircuit{},
+		test.WithValidAssignment(valid),
+		test.WithCurves(ecc.BN254),
+	)
+}
+
+// instanceCommitmentCircuit exercises calcInstanceCommitment's BN254 fast
+// path (bn254Curve.bn254Api.BN254MultiScalarMul over instanceBasisBN254)
+// against a result independently computed with plain bn254.G1Affine
+// arithmetic, so a regression in the MSM it now calls into - rather than
+// the sequential ScalarMulConstant+Add loop chunk0-3 originally wired up -
+// would be caught here rather than only downstream in verify.
+type instanceCommitmentCircuit struct {
+	BasisXY   [][2]big.Int
+	Instances []frontend.Variable
+	Expected  [2]big.Int
+}
+
+func (c *instanceCommitmentCircuit) Define(api frontend.API) error {
+	u64Api, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	u256Api := NewU256API(api, u64Api)
+	bn254Api, err := NewBN254API(api, u256Api)
+	if err != nil {
+		return err
+	}
+
+	basis := make([][]string, len(c.BasisXY))
+	for i := range c.BasisXY {
+		basis[i] = []string{c.BasisXY[i][0].String(), c.BasisXY[i][1].String()}
+	}
+
+	halo2Api, err := NewHalo2VerifierAPI(Halo2VerifierConfig{
+		VerifyCircuitGLagrange: basis,
+	}, api, u64Api, u256Api, bn254Api)
+	if err != nil {
+		return err
+	}
+
+	got, err := halo2Api.calcInstanceCommitment(c.Instances)
+	if err != nil {
+		return err
+	}
+
+	expected := bn254Api.BN254FromConstant(c.Expected)
+	point := got.(*sw_emulated.AffinePoint[emparams.BN254Fp])
+	bn254Api.fpFieldApi.AssertIsEqual(&point.X, &expected.X)
+	bn254Api.fpFieldApi.AssertIsEqual(&point.Y, &expected.Y)
+	return nil
+}
+
+func TestCalcInstanceCommitmentMatchesPlainMSM(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	_, _, g1Gen, _ := bn254.Generators()
+
+	instances := []int64{5, 12345, 987654321}
+	basisXY := make([][2]big.Int, len(instances))
+	var expected bn254.G1Affine
+	for i, v := range instances {
+		var p bn254.G1Affine
+		p.ScalarMultiplication(&g1Gen, big.NewInt(int64(2*i+3)))
+		p.X.BigInt(&basisXY[i][0])
+		p.Y.BigInt(&basisXY[i][1])
+
+		var term bn254.G1Affine
+		term.ScalarMultiplication(&p, big.NewInt(v))
+		if i == 0 {
+			expected = term
+		} else {
+			expected.Add(&expected, &term)
+		}
+	}
+
+	var expectedConst [2]big.Int
+	expected.X.BigInt(&expectedConst[0])
+	expected.Y.BigInt(&expectedConst[1])
+
+	circuitInstances := make([]frontend.Variable, len(instances))
+	for i, v := range instances {
+		circuitInstances[i] = v
+	}
+	valid := &instanceCommitmentCircuit{BasisXY: basisXY, Instances: circuitInstances, Expected: expectedConst}
+	placeholder := &instanceCommitmentCircuit{BasisXY: basisXY, Instances: make([]frontend.Variable, len(instances)), Expected: expectedConst}
+
+	assert.CheckCircuit(placeholder,
+		test.WithValidAssignment(valid),
+		test.WithCurves(ecc.BN254),
+	)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"strconv"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
@@ -29,146 +31,235 @@ import (
 
 var (
 	DIR                 = "gnark_setup"
-	Groth16PkPath       = fmt.Sprintf("%s/groth16_pk", DIR)
-	Groth16VkPath       = fmt.Sprintf("%s/groth16_vk", DIR)
 	Groth16VerifierPath = fmt.Sprintf("%s/contract_groth16.sol", DIR)
-
-	PlonkPkPath          = fmt.Sprintf("%s/plonk_pk", DIR)
-	PlonkVkPath          = fmt.Sprintf("%s/plonk_vk", DIR)
-	PlonkVerifierPath    = fmt.Sprintf("%s/contract_plonk.sol", DIR)
-	PlonkSrsName         = fmt.Sprintf("%s/plonk_srs", DIR)
-	PlonkSrsLagrangeName = fmt.Sprintf("%s/plonk_srsLagrange", DIR)
+	PlonkVerifierPath   = fmt.Sprintf("%s/contract_plonk.sol", DIR)
 )
 
-func GeneratePkVk(cs constraint.ConstraintSystem, prover backend.ID) (any, any) {
-	var pk, vk any
-	var err error
-	switch prover {
-	case backend.GROTH16:
-		pk, vk, err = groth16.Setup(cs)
-		if err != nil {
-			log.Fatalln(err)
-		}
-	case backend.PLONK:
-		var srs, srsLagrange kzg.SRS
-		if _, err := os.Stat(PlonkSrsName); errors.Is(err, os.ErrNotExist) {
-			srs, srsLagrange, err := unsafekzg.NewSRS(cs)
-			if err != nil {
-				panic(err)
-			}
-			fSrs, err := os.Create(PlonkSrsName)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			_, err = srs.WriteRawTo(fSrs)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			fSrsLagrange, err := os.Create(PlonkSrsLagrangeName)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			_, err = srsLagrange.WriteRawTo(fSrsLagrange)
-			if err != nil {
-				log.Fatalln(err)
-			}
-		} else {
-			fSrs, err := os.Open(PlonkSrsName)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			_, err = srs.ReadFrom(fSrs)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			fSrsLagrange, err := os.Open(PlonkSrsLagrangeName)
-			if err != nil {
-				log.Fatalln(err)
-			}
-			_, err = srsLagrange.ReadFrom(fSrsLagrange)
-			if err != nil {
-				log.Fatalln(err)
-			}
-		}
-		pk, vk, err = plonk.Setup(cs, srs, srsLagrange)
-		if err != nil {
-			log.Fatalln(err)
-		}
-	default:
-		panic("unhandled default case")
+// SetupCache is the on-disk pk/vk (and, for PLONK, KZG SRS) for one
+// compiled circuit, stored under a directory named after the circuit's
+// digest (see ccsDigest) so that recompiling the same circuit reuses the
+// same setup artifacts and any change to the circuit - which changes the
+// digest - can't be served stale pk/vk generated for a different one.
+type SetupCache struct {
+	dir             string
+	pkPath          string
+	vkPath          string
+	srsPath         string
+	srsLagrangePath string
+}
+
+// ccsDigest hashes the serialized constraint system with SHA-256, giving
+// SetupCache a stable, content-addressed key that changes whenever the
+// compiled circuit does.
+func ccsDigest(ccs constraint.ConstraintSystem) (string, error) {
+	h := sha256.New()
+	if _, err := ccs.WriteTo(h); err != nil {
+		return "", fmt.Errorf("ccsDigest: %w", err)
 	}
-	SavePkVk(pk.(gnarkio.WriterRawTo), vk.(gnarkio.WriterRawTo), prover)
-	return pk, vk
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func newSetupCache(ccs constraint.ConstraintSystem, id backend.ID) (*SetupCache, error) {
+	digest, err := ccsDigest(ccs)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := fmt.Sprintf("%s/%s-%s", DIR, id, digest)
+	c := &SetupCache{
+		dir:    dir,
+		pkPath: fmt.Sprintf("%s/pk", dir),
+		vkPath: fmt.Sprintf("%s/vk", dir),
+	}
+	if id == backend.PLONK {
+		c.srsPath = fmt.Sprintf("%s/srs", dir)
+		c.srsLagrangePath = fmt.Sprintf("%s/srsLagrange", dir)
+	}
+	return c, nil
+}
+
+func (c *SetupCache) exists() bool {
+	_, pkErr := os.Stat(c.pkPath)
+	_, vkErr := os.Stat(c.vkPath)
+	return pkErr == nil && vkErr == nil
 }
 
-func SavePkVk(pk, vk gnarkio.WriterRawTo, id backend.ID) {
-	var pkPath, vkPath string
+func (c *SetupCache) load(id backend.ID, curveID ecc.ID) (pk, vk any, err error) {
+	var pkR, vkR io.ReaderFrom
 	switch id {
 	case backend.GROTH16:
-		pkPath = Groth16PkPath
-		vkPath = Groth16VkPath
+		pkR, vkR = groth16.NewProvingKey(curveID), groth16.NewVerifyingKey(curveID)
 	case backend.PLONK:
-		pkPath = PlonkPkPath
-		vkPath = PlonkVkPath
+		pkR, vkR = plonk.NewProvingKey(curveID), plonk.NewVerifyingKey(curveID)
 	default:
-		panic("unhandled default case")
+		return nil, nil, fmt.Errorf("SetupCache.load: unhandled backend %s", id)
 	}
 
-	fpk, err := os.Create(pkPath)
+	fpk, err := os.Open(c.pkPath)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, nil, err
+	}
+	defer fpk.Close()
+	if _, err := pkR.ReadFrom(fpk); err != nil {
+		return nil, nil, err
 	}
-	_, err = pk.WriteRawTo(fpk)
+
+	fvk, err := os.Open(c.vkPath)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, nil, err
 	}
+	defer fvk.Close()
+	if _, err := vkR.ReadFrom(fvk); err != nil {
+		return nil, nil, err
+	}
+
+	return pkR, vkR, nil
+}
 
-	fvk, err := os.Create(vkPath)
+func (c *SetupCache) save(pk, vk gnarkio.WriterRawTo) error {
+	fpk, err := os.Create(c.pkPath)
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
-	_, err = vk.WriteRawTo(fvk)
+	defer fpk.Close()
+	if _, err := pk.WriteRawTo(fpk); err != nil {
+		return err
+	}
+
+	fvk, err := os.Create(c.vkPath)
 	if err != nil {
-		log.Fatalln(err)
+		return err
+	}
+	defer fvk.Close()
+	if _, err := vk.WriteRawTo(fvk); err != nil {
+		return err
 	}
+	return nil
 }
 
-func ReadPkVk(id backend.ID, curveID ecc.ID) (any, any) {
-	var pk, vk io.ReaderFrom
-	var pkPath, vkPath string
+// loadOrCreatePlonkSRS returns the KZG SRS (and its Lagrange-basis
+// companion) for ccs, loading it from c.srsPath/c.srsLagrangePath when
+// present and generating + persisting a fresh one otherwise. The
+// generated srs/srsLagrange are returned directly to the caller, fixing a
+// bug in the code this replaces where the miss-path shadowed them inside
+// an inner `:=` block, so plonk.Setup was always handed the zero-value
+// SRS declared in the outer scope.
+func loadOrCreatePlonkSRS(ccs constraint.ConstraintSystem, c *SetupCache) (kzg.SRS, kzg.SRS, error) {
+	var srs, srsLagrange kzg.SRS
+
+	if _, err := os.Stat(c.srsPath); err == nil {
+		fSrs, err := os.Open(c.srsPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer fSrs.Close()
+		if _, err := srs.ReadFrom(fSrs); err != nil {
+			return nil, nil, err
+		}
+
+		fSrsLagrange, err := os.Open(c.srsLagrangePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer fSrsLagrange.Close()
+		if _, err := srsLagrange.ReadFrom(fSrsLagrange); err != nil {
+			return nil, nil, err
+		}
+		return srs, srsLagrange, nil
+	}
+
+	srs, srsLagrange, srsErr := unsafekzg.NewSRS(ccs)
+	if srsErr != nil {
+		return nil, nil, srsErr
+	}
+
+	fSrs, err := os.Create(c.srsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fSrs.Close()
+	if _, err := srs.WriteRawTo(fSrs); err != nil {
+		return nil, nil, err
+	}
+
+	fSrsLagrange, err := os.Create(c.srsLagrangePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fSrsLagrange.Close()
+	if _, err := srsLagrange.WriteRawTo(fSrsLagrange); err != nil {
+		return nil, nil, err
+	}
+
+	return srs, srsLagrange, nil
+}
+
+func (c *SetupCache) setup(ccs constraint.ConstraintSystem, id backend.ID) (pk, vk any, err error) {
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return nil, nil, err
+	}
+
 	switch id {
 	case backend.GROTH16:
-		pkPath = Groth16PkPath
-		vkPath = Groth16VkPath
-		pk = groth16.NewProvingKey(curveID)
-		vk = groth16.NewVerifyingKey(curveID)
+		pk, vk, err = groth16.Setup(ccs)
+		if err != nil {
+			return nil, nil, err
+		}
 	case backend.PLONK:
-		pkPath = Groth16PkPath
-		vkPath = Groth16VkPath
-		pk = plonk.NewProvingKey(curveID)
-		vk = plonk.NewVerifyingKey(curveID)
+		srs, srsLagrange, err := loadOrCreatePlonkSRS(ccs, c)
+		if err != nil {
+			return nil, nil, err
+		}
+		pk, vk, err = plonk.Setup(ccs, srs, srsLagrange)
+		if err != nil {
+			return nil, nil, err
+		}
 	default:
-		panic("unhandled default case")
+		return nil, nil, fmt.Errorf("SetupCache.setup: unhandled backend %s", id)
 	}
 
-	fpk, err := os.Open(pkPath)
-	if err != nil {
-		log.Fatalln(err)
+	if err := c.save(pk.(gnarkio.WriterRawTo), vk.(gnarkio.WriterRawTo)); err != nil {
+		return nil, nil, err
 	}
-	_, err = pk.ReadFrom(fpk)
+	return pk, vk, nil
+}
+
+// LoadOrSetup returns the proving/verifying key for ccs: it reuses the
+// on-disk SetupCache keyed by ccs's digest when one exists and
+// deserializes cleanly for id/curveID, and otherwise falls back to
+// generating (and caching) a fresh pk/vk. It replaces
+// GeneratePkVk/ReadPkVk/SavePkVk and their fixed path constants, the
+// latter of which had a bug: ReadPkVk read the Groth16 paths for both the
+// GROTH16 and PLONK branches, so a PLONK run could silently load (and
+// fail to deserialize as) a Groth16 key.
+func LoadOrSetup(ccs constraint.ConstraintSystem, id backend.ID, curveID ecc.ID) (pk, vk any, err error) {
+	cache, err := newSetupCache(ccs, id)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, nil, err
 	}
 
-	fvk, err := os.Open(vkPath)
-	if err != nil {
-		log.Fatalln(err)
+	if cache.exists() {
+		if pk, vk, err = cache.load(id, curveID); err == nil {
+			return pk, vk, nil
+		}
+		log.Printf("LoadOrSetup: cached pk/vk under %s failed to load (%v), regenerating", cache.dir, err)
 	}
-	_, err = vk.ReadFrom(fvk)
+
+	return cache.setup(ccs, id)
+}
+
+// LoadCached loads a previously generated pk/vk for ccs from its
+// SetupCache without ever generating a fresh one, so a `-setup=false` run
+// fails fast instead of silently reusing another circuit's keys.
+func LoadCached(ccs constraint.ConstraintSystem, id backend.ID, curveID ecc.ID) (pk, vk any, err error) {
+	cache, err := newSetupCache(ccs, id)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, nil, err
 	}
-	return pk, vk
+	if !cache.exists() {
+		return nil, nil, fmt.Errorf("LoadCached: no cached pk/vk under %s for this circuit", cache.dir)
+	}
+	return cache.load(id, curveID)
 }
 
 // Compile the given circuit for given curve and backend, if not already present in cache
@@ -202,27 +293,42 @@ func Compile(circuit frontend.Circuit, curveID ecc.ID, backendID backend.ID, com
 	return ccs, nil
 }
 
-type fnSetup func(ccs constraint.ConstraintSystem, curve ecc.ID) (any, any, error)
+// fnSetup produces the proving/verifying key for a compiled circuit.
+// isSetup mirrors the -setup CLI flag in main.go: true runs LoadOrSetup
+// (reusing a cached pk/vk keyed by the circuit's digest, generating one on
+// a miss), false expects a cache entry to already exist and only loads it
+// via LoadCached.
+type fnSetup func(ccs constraint.ConstraintSystem, curve ecc.ID, isSetup bool) (any, any, error)
 type fnProve func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error)
 type fnVerify func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error
 
+// fnVerifyPedersen natively re-verifies a proof's BSB22/Pedersen
+// commitment groups (see VerifyPedersenBatch), independent of the
+// backend's own Verify. It closes over whatever PedersenVK the backend's
+// circuit was set up with, so it only needs the commitments, the
+// knowledge-of-opening proofs, and the public inputs each group commits
+// to.
+type fnVerifyPedersen func(commitments, poks []bn254.G1Affine, publicInputs [][]fr_bn254.Element) error
+
 // Backend abstracts the backend implementation in the test package.
 type Backend struct {
 	Setup  fnSetup
 	Prove  fnProve
 	Verify fnVerify
+	// VerifyPedersen, when set, lets a caller natively re-check a proof's
+	// BSB22/Pedersen commitment groups via VerifyPedersenBatch. Left nil
+	// for backends with no commitment scheme (e.g. PlonkBackend) or when
+	// the circuit makes no api.Commit calls.
+	VerifyPedersen fnVerifyPedersen
 }
 
 var (
 	GrothBackend = Backend{
-		Setup: func(ccs constraint.ConstraintSystem, curve ecc.ID) (any, any, error) {
-			if _, err := os.Stat("gnark_setup"); os.IsNotExist(err) {
-				if err := os.Mkdir("gnark_setup", os.ModePerm); err != nil {
-					panic(err)
-				}
+		Setup: func(ccs constraint.ConstraintSystem, curve ecc.ID, isSetup bool) (any, any, error) {
+			if isSetup {
+				return LoadOrSetup(ccs, backend.GROTH16, curve)
 			}
-			pk, vk := GeneratePkVk(ccs, backend.GROTH16)
-			return pk, vk, nil
+			return LoadCached(ccs, backend.GROTH16, curve)
 		},
 		Prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
 			return groth16.Prove(ccs, pk.(groth16.ProvingKey), fullWitness, opts...)
@@ -233,14 +339,11 @@ var (
 	}
 
 	PlonkBackend = Backend{
-		Setup: func(ccs constraint.ConstraintSystem, curve ecc.ID) (any, any, error) {
-			if _, err := os.Stat("gnark_setup"); os.IsNotExist(err) {
-				if err := os.Mkdir("gnark_setup", os.ModePerm); err != nil {
-					panic(err)
-				}
+		Setup: func(ccs constraint.ConstraintSystem, curve ecc.ID, isSetup bool) (any, any, error) {
+			if isSetup {
+				return LoadOrSetup(ccs, backend.PLONK, curve)
 			}
-			pk, vk := GeneratePkVk(ccs, backend.GROTH16)
-			return pk, vk, nil
+			return LoadCached(ccs, backend.PLONK, curve)
 		},
 		Prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
 			return plonk.Prove(ccs, pk.(plonk.ProvingKey), fullWitness, opts...)
@@ -361,28 +464,118 @@ func groth16VerifyCallData(proofHex, inputHex string) {
 	c := new(big.Int).SetBytes(proofBytes[fpSize*8 : fpSize*8+4])
 	commitmentCount := int(c.Int64())
 
-	if commitmentCount != 1 {
-		panic("commitmentCount != .NbCommitments")
+	// commitments and commitmentPoks each hold 2*commitmentCount field
+	// elements - one (x, y) G1 pair per BSB22/Pedersen commitment group -
+	// matching the Solidity verifier's flattened calldata layout for any N,
+	// rather than the single hardcoded pair this used to assume.
+	commitmentsBase := fpSize*8 + 4
+	commitments := make([]*big.Int, 2*commitmentCount)
+	for i := range commitments {
+		commitments[i] = new(big.Int).SetBytes(proofBytes[commitmentsBase+i*fpSize : commitmentsBase+(i+1)*fpSize])
 	}
 
-	var commitments [2]*big.Int
-	var commitmentPok [2]*big.Int
-
-	// commitments
-	for i := 0; i < 2*commitmentCount; i++ {
-		commitments[i] = new(big.Int).SetBytes(proofBytes[fpSize*8+4+i*fpSize : fpSize*8+4+(i+1)*fpSize])
+	commitmentPoksBase := commitmentsBase + 2*commitmentCount*fpSize
+	commitmentPoks := make([]*big.Int, 2*commitmentCount)
+	for i := range commitmentPoks {
+		commitmentPoks[i] = new(big.Int).SetBytes(proofBytes[commitmentPoksBase+i*fpSize : commitmentPoksBase+(i+1)*fpSize])
 	}
 
-	// commitmentPok
-	commitmentPok[0] = new(big.Int).SetBytes(proofBytes[fpSize*8+4+2*commitmentCount*fpSize : fpSize*8+4+2*commitmentCount*fpSize+fpSize])
-	commitmentPok[1] = new(big.Int).SetBytes(proofBytes[fpSize*8+4+2*commitmentCount*fpSize+fpSize : fpSize*8+4+2*commitmentCount*fpSize+2*fpSize])
-
 	outputBigIntArray("proof", proof[:])
-	outputBigIntArray("commitments", commitments[:])
-	outputBigIntArray("commitmentPoks", commitmentPok[:])
+	outputBigIntArray("commitments", commitments)
+	outputBigIntArray("commitmentPoks", commitmentPoks)
 	outputBigIntArray("inputs", input[:])
 }
 
+// PedersenVK holds the verifying-key material gnark's Groth16 backend
+// pairs a BSB22/Pedersen commitment group's (commitment, pok) against: a
+// single G2 point and -[sigma^-1]G2, shared across every commitment group
+// in the proof (only the G1 bases differ per group, which is what makes
+// the scheme "multi-basis").
+type PedersenVK struct {
+	G2            bn254.G2Affine
+	GRootSigmaNeg bn254.G2Affine
+}
+
+// pedersenBatchChallenge derives the Fiat-Shamir scalar r that
+// VerifyPedersenBatch folds commitment groups by: it hashes every
+// commitment's marshaled bytes followed by every public input's
+// marshaled bytes through sha256, the same hash gnark's own
+// backend.With{Prover,Verifier}HashToFieldFunction wires up for BSB22
+// commitments elsewhere in this package (see main.go).
+func pedersenBatchChallenge(commitments []bn254.G1Affine, publicInputs [][]fr_bn254.Element) (fr_bn254.Element, error) {
+	h := sha256.New()
+	for i := range commitments {
+		b := commitments[i].Marshal()
+		if _, err := h.Write(b); err != nil {
+			return fr_bn254.Element{}, err
+		}
+	}
+	for _, group := range publicInputs {
+		for _, e := range group {
+			b := e.Marshal()
+			if _, err := h.Write(b); err != nil {
+				return fr_bn254.Element{}, err
+			}
+		}
+	}
+
+	var r fr_bn254.Element
+	r.SetBytes(h.Sum(nil))
+	return r, nil
+}
+
+// VerifyPedersenBatch runs gnark's standard batched BSB22/Pedersen
+// knowledge-of-opening check across N commitment groups: it samples the
+// Fiat-Shamir challenge r from pedersenBatchChallenge, folds
+// C = sum(r^i * commitments[i]) and P = sum(r^i * poks[i]), and asserts
+// e(C, -[sigma^-1]G2) * e(P, G2) == 1 with a single PairingCheck. This
+// lets a caller who built a circuit with multiple api.Commit calls (each
+// producing its own basis/commitment) re-verify all of them natively,
+// without hand-patching the Solidity calldata layout groth16VerifyCallData
+// produces.
+func VerifyPedersenBatch(vk PedersenVK, commitments, poks []bn254.G1Affine, publicInputs [][]fr_bn254.Element) error {
+	if len(commitments) != len(poks) || len(commitments) != len(publicInputs) {
+		return fmt.Errorf("VerifyPedersenBatch: mismatched lengths: %d commitments, %d poks, %d publicInputs", len(commitments), len(poks), len(publicInputs))
+	}
+	if len(commitments) == 0 {
+		return nil
+	}
+
+	r, err := pedersenBatchChallenge(commitments, publicInputs)
+	if err != nil {
+		return fmt.Errorf("VerifyPedersenBatch: %w", err)
+	}
+
+	var C, P bn254.G1Affine
+	rPow := fr_bn254.One()
+	for i := range commitments {
+		var rBig big.Int
+		rPow.BigInt(&rBig)
+
+		var weightedC, weightedP bn254.G1Affine
+		weightedC.ScalarMultiplication(&commitments[i], &rBig)
+		weightedP.ScalarMultiplication(&poks[i], &rBig)
+
+		if i == 0 {
+			C, P = weightedC, weightedP
+		} else {
+			C.Add(&C, &weightedC)
+			P.Add(&P, &weightedP)
+		}
+
+		rPow.Mul(&rPow, &r)
+	}
+
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{C, P}, []bn254.G2Affine{vk.GRootSigmaNeg, vk.G2})
+	if err != nil {
+		return fmt.Errorf("VerifyPedersenBatch: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("VerifyPedersenBatch: pairing check failed")
+	}
+	return nil
+}
+
 func plonkVerifyCallData(proofHex, inputHex string) {
 	proofBytes, err := hex.DecodeString(proofHex)
 	if err != nil {
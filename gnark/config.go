@@ -15,4 +15,40 @@ type Halo2VerifierConfig struct {
 	NbLookupsZs            uint32     `json:"nb_lookups_zs"`
 	NbPermutationGroups    uint32     `json:"nb_permutation_groups"`
 	NbEvals                uint32     `json:"nb_evals"`
+	// NbShiftedEvals is the number of opened commitments, counted from
+	// right after the NbAdvices+NbLookupsM commitments in the multiopen
+	// list built by getChallengesShPlonkCircuit (the permutation/lookup
+	// grand-product polynomials), that are opened a second time at the
+	// shifted point zeta*Omega in addition to their normal zeta opening.
+	// verify folds these into a second batch checked against the shifted
+	// opening proof instead of lumping every opening into a single
+	// zeta-only check.
+	NbShiftedEvals uint32 `json:"nb_shifted_evals"`
+	// Omega is the proof's evaluation-domain generator, used to derive the
+	// shifted evaluation point zeta*Omega that NbShiftedEvals's openings
+	// are checked against.
+	Omega string `json:"omega"`
+	// TranscriptHash selects the Fiat-Shamir hash used to derive challenges:
+	// "sha256" (default, matches the native Halo2 transcript) or "poseidon"
+	// (an algebraic hash that is far cheaper to evaluate in-circuit, for
+	// setups that also control the prover-side transcript).
+	TranscriptHash string `json:"transcript_hash"`
+	// BatchSize is the number of Halo2 proofs verified together inside a
+	// single Halo2VerifierCircuit, collapsed into one pairing check. Zero
+	// and one are equivalent to verifying a single proof.
+	BatchSize uint32 `json:"batch_size"`
+	// ProofCurve selects the pairing-friendly curve the Halo2 proof itself
+	// was produced over: "bn254" (default) or "bls12381". It is unrelated
+	// to the curve this gnark circuit is compiled/proved over, which keeps
+	// using whatever -curveID/-backendID the outer wrapper is run with.
+	ProofCurve string `json:"proof_curve"`
+	// PedersenBasesG1 holds, per committed column, the G1 bases the prover
+	// used to form that column's Pedersen/BSB22-style commitment.
+	PedersenBasesG1 [][][]string `json:"pedersen_bases_g1"`
+	// PedersenG2 holds, per committed column, the G2 point paired against
+	// that column's knowledge-of-opening proof in verifyPedersenKnowledge.
+	PedersenG2 [][]string `json:"pedersen_g2"`
+	// PedersenGRootSigmaNeg holds, per committed column, -[sigma^-1]G2,
+	// paired against that column's commitment in verifyPedersenKnowledge.
+	PedersenGRootSigmaNeg [][]string `json:"pedersen_g_root_sigma_neg"`
 }
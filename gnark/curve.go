@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Halo2Curve abstracts the pairing-friendly curve a Halo2 proof was
+// produced over, so the transcript and the final pairing check don't need
+// to be duplicated per curve. Proof-derived values crossing this interface
+// (field-element "words" and curve points) are boxed as `any`: BN254Curve
+// only ever unboxes U256/*sw_emulated.AffinePoint[emparams.BN254Fp] and
+// BLS12381Curve only ever unboxes U384/*sw_emulated.AffinePoint[emparams.BLS12381Fp].
+type Halo2Curve interface {
+	// ParseProofWords splits a flat per-byte proof transcript into this
+	// curve's field-element words (U256 for BN254, U384 for BLS12-381).
+	ParseProofWords(u64Api *uints.BinaryField[uints.U64], proof []frontend.Variable) ([]any, error)
+	// AssertOnCurve builds and constrains a G1 point from an (x, y) word
+	// pair, as read off the proof transcript.
+	AssertOnCurve(xWord, yWord any) any
+	// PointXY splits a G1 point back into its coordinate words, so they can
+	// be absorbed into the transcript (e.g. instance commitments, the
+	// batch's pairing pairs).
+	PointXY(point any) (xWord, yWord any)
+	// WordBytesBE renders a word as big-endian bytes, for the byte-oriented
+	// SHA-256 transcript.
+	WordBytesBE(word any) []uints.U8
+	// WordToValue renders a word as a single native scalar, for the
+	// algebraic (poseidon) transcript and for extracting eval values.
+	WordToValue(word any) frontend.Variable
+	ScalarMulConstant(point [2]big.Int, scalar frontend.Variable) any
+	ScalarMul(point any, scalar frontend.Variable) any
+	AddG1(a, b any) any
+	ScalarMulAndAddG1(point any, scalar frontend.Variable, b any) any
+	Neg(point any) any
+	// G1Gen returns this curve's standard G1 generator, used to turn a
+	// claimed scalar evaluation into a point for the batched KZG pairing
+	// check in Halo2VerifierAPI.verify.
+	G1Gen() [2]big.Int
+	G2Affines(config Halo2VerifierConfig) ([]any, error)
+	// ParseG2Points parses a list of [a0, a1, b0, b1] decimal-string G2
+	// coordinates into constant G2 points, the same format used for
+	// VerifyCircuitG2Affine. It backs both GetVerifyCircuitsG2Affine and the
+	// per-column Pedersen G2 constants.
+	ParseG2Points(vals [][]string) ([]any, error)
+	PairingCheck(p1, p2 []any, g2 []any) error
+}
+
+// newHalo2Curve dispatches on config.ProofCurve, reusing the bn254Api that
+// NewHalo2VerifierAPI already builds for instance commitments when the
+// proof curve is BN254, so it isn't constructed twice.
+func newHalo2Curve(api frontend.API, u64Api *uints.BinaryField[uints.U64], bn254Api *BN254API, config Halo2VerifierConfig) (Halo2Curve, error) {
+	switch config.ProofCurve {
+	case "", "bn254":
+		return &BN254Curve{api: api, bn254Api: bn254Api}, nil
+	case "bls12381":
+		u384Api := NewU384API(api, u64Api)
+		blsApi, err := NewBLS12381API(api, u384Api)
+		if err != nil {
+			return nil, err
+		}
+		return &BLS12381Curve{api: api, blsApi: blsApi}, nil
+	default:
+		return nil, fmt.Errorf("unsupported proof_curve %q", config.ProofCurve)
+	}
+}
+
+// BN254Curve is the default Halo2Curve, matching the verifier's original
+// hardwired BN254 behaviour.
+type BN254Curve struct {
+	api      frontend.API
+	bn254Api *BN254API
+}
+
+// bn254G1Gen is the standard BN254 G1 generator (1, 2).
+var bn254G1Gen = [2]big.Int{*big.NewInt(1), *big.NewInt(2)}
+
+func (c *BN254Curve) ParseProofWords(u64Api *uints.BinaryField[uints.U64], proof []frontend.Variable) ([]any, error) {
+	if len(proof)%32 != 0 {
+		return nil, fmt.Errorf("invalid proof size")
+	}
+
+	words := make([]any, len(proof)/32)
+	for i := range words {
+		var w U256
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 8; k++ {
+				w[j][k] = u64Api.ByteValueOf(proof[i*32+j*8+k])
+			}
+		}
+		words[i] = w
+	}
+
+	return words, nil
+}
+
+func (c *BN254Curve) AssertOnCurve(xWord, yWord any) any {
+	return c.bn254Api.AssertOnCurve(xWord.(U256), yWord.(U256))
+}
+
+func (c *BN254Curve) PointXY(point any) (any, any) {
+	p := point.(*sw_emulated.AffinePoint[emparams.BN254Fp])
+	return c.bn254Api.BN254FpToU256(&p.X), c.bn254Api.BN254FpToU256(&p.Y)
+}
+
+func (c *BN254Curve) WordBytesBE(word any) []uints.U8 {
+	x := word.(U256)
+	bytes := make([]uints.U8, 0, 32)
+	for i := 3; i >= 0; i-- {
+		for j := 7; j >= 0; j-- {
+			bytes = append(bytes, x[i][j])
+		}
+	}
+	return bytes
+}
+
+func (c *BN254Curve) WordToValue(word any) frontend.Variable {
+	bits := c.bn254Api.u256Api.ToBits(word.(U256))
+	return c.api.FromBinary(bits...)
+}
+
+func (c *BN254Curve) ScalarMulConstant(point [2]big.Int, scalar frontend.Variable) any {
+	return c.bn254Api.BN254ScalarMulConstant(point, scalar)
+}
+
+func (c *BN254Curve) ScalarMul(point any, scalar frontend.Variable) any {
+	return c.bn254Api.BN254ScalarMul(point.(*sw_emulated.AffinePoint[emparams.BN254Fp]), scalar)
+}
+
+func (c *BN254Curve) AddG1(a, b any) any {
+	return c.bn254Api.BN254AddG1(
+		a.(*sw_emulated.AffinePoint[emparams.BN254Fp]),
+		b.(*sw_emulated.AffinePoint[emparams.BN254Fp]),
+	)
+}
+
+func (c *BN254Curve) ScalarMulAndAddG1(point any, scalar frontend.Variable, b any) any {
+	return c.bn254Api.BN254ScalarMulAndAddG1(
+		point.(*sw_emulated.AffinePoint[emparams.BN254Fp]),
+		scalar,
+		b.(*sw_emulated.AffinePoint[emparams.BN254Fp]),
+	)
+}
+
+func (c *BN254Curve) Neg(point any) any {
+	return c.bn254Api.curveApi.Neg(point.(*sw_emulated.AffinePoint[emparams.BN254Fp]))
+}
+
+func (c *BN254Curve) G1Gen() [2]big.Int {
+	return bn254G1Gen
+}
+
+func (c *BN254Curve) G2Affines(config Halo2VerifierConfig) ([]any, error) {
+	return c.ParseG2Points(config.VerifyCircuitG2Affine)
+}
+
+func (c *BN254Curve) ParseG2Points(vals [][]string) ([]any, error) {
+	res := make([]any, len(vals))
+
+	for i := range vals {
+		g := bn254.G2Affine{}
+		g.X.SetString(vals[i][0], vals[i][1])
+		g.Y.SetString(vals[i][2], vals[i][3])
+		if !g.IsOnCurve() {
+			return nil, fmt.Errorf("invalid g2 at %d", i)
+		}
+		v := sw_bn254.NewG2Affine(g)
+		res[i] = &v
+	}
+
+	return res, nil
+}
+
+func (c *BN254Curve) PairingCheck(p1, p2 []any, g2 []any) error {
+	pairingApi, err := sw_bn254.NewPairing(c.api)
+	if err != nil {
+		return fmt.Errorf("NewPairing: %w", err)
+	}
+
+	points := make([]*sw_emulated.AffinePoint[emparams.BN254Fp], len(p1)+len(p2))
+	for i := range p1 {
+		points[i] = p1[i].(*sw_emulated.AffinePoint[emparams.BN254Fp])
+	}
+	for i := range p2 {
+		points[len(p1)+i] = p2[i].(*sw_emulated.AffinePoint[emparams.BN254Fp])
+	}
+
+	g2Points := make([]*sw_bn254.G2Affine, len(g2))
+	for i := range g2 {
+		g2Points[i] = g2[i].(*sw_bn254.G2Affine)
+	}
+
+	return pairingApi.PairingCheck(points, g2Points)
+}
+
+// BLS12381Curve lets the wrapper aggregate Halo2 proofs produced on
+// BLS12-381, common in non-Ethereum zk stacks, while the outer wrapper
+// keeps proving with Groth16/PLONK over BN254.
+type BLS12381Curve struct {
+	api    frontend.API
+	blsApi *BLS12381API
+}
+
+func (c *BLS12381Curve) ParseProofWords(u64Api *uints.BinaryField[uints.U64], proof []frontend.Variable) ([]any, error) {
+	if len(proof)%48 != 0 {
+		return nil, fmt.Errorf("invalid proof size")
+	}
+
+	words := make([]any, len(proof)/48)
+	for i := range words {
+		var w U384
+		for j := 0; j < 6; j++ {
+			for k := 0; k < 8; k++ {
+				w[j][k] = u64Api.ByteValueOf(proof[i*48+j*8+k])
+			}
+		}
+		words[i] = w
+	}
+
+	return words, nil
+}
+
+func (c *BLS12381Curve) AssertOnCurve(xWord, yWord any) any {
+	return c.blsApi.AssertOnCurve(xWord.(U384), yWord.(U384))
+}
+
+func (c *BLS12381Curve) PointXY(point any) (any, any) {
+	p := point.(*sw_emulated.AffinePoint[emparams.BLS12381Fp])
+	return c.blsApi.BLS12381FpToU384(&p.X), c.blsApi.BLS12381FpToU384(&p.Y)
+}
+
+func (c *BLS12381Curve) WordBytesBE(word any) []uints.U8 {
+	x := word.(U384)
+	bytes := make([]uints.U8, 0, 48)
+	for i := 5; i >= 0; i-- {
+		for j := 7; j >= 0; j-- {
+			bytes = append(bytes, x[i][j])
+		}
+	}
+	return bytes
+}
+
+func (c *BLS12381Curve) WordToValue(word any) frontend.Variable {
+	bits := c.blsApi.u384Api.ToBits(word.(U384))
+	return c.api.FromBinary(bits...)
+}
+
+func (c *BLS12381Curve) ScalarMulConstant(point [2]big.Int, scalar frontend.Variable) any {
+	return c.blsApi.BLS12381ScalarMulConstant(point, scalar)
+}
+
+func (c *BLS12381Curve) ScalarMul(point any, scalar frontend.Variable) any {
+	return c.blsApi.BLS12381ScalarMul(point.(*sw_emulated.AffinePoint[emparams.BLS12381Fp]), scalar)
+}
+
+func (c *BLS12381Curve) AddG1(a, b any) any {
+	return c.blsApi.BLS12381AddG1(
+		a.(*sw_emulated.AffinePoint[emparams.BLS12381Fp]),
+		b.(*sw_emulated.AffinePoint[emparams.BLS12381Fp]),
+	)
+}
+
+func (c *BLS12381Curve) ScalarMulAndAddG1(point any, scalar frontend.Variable, b any) any {
+	return c.blsApi.BLS12381ScalarMulAndAddG1(
+		point.(*sw_emulated.AffinePoint[emparams.BLS12381Fp]),
+		scalar,
+		b.(*sw_emulated.AffinePoint[emparams.BLS12381Fp]),
+	)
+}
+
+func (c *BLS12381Curve) Neg(point any) any {
+	return c.blsApi.curveApi.Neg(point.(*sw_emulated.AffinePoint[emparams.BLS12381Fp]))
+}
+
+// bls12381G1Gen is the standard BLS12-381 G1 generator, the BLS12-381
+// analogue of bn254G1Gen.
+var bls12381G1Gen = [2]big.Int{
+	*bigIntFromString("3685416753713387016781088315183077757961620795782546409894578378688607592378376318836054947676345821548104185464507"),
+	*bigIntFromString("1339506544944476473020471379941921221584933875938349620426543736416511423956333506472724655353366534992391756441569"),
+}
+
+func bigIntFromString(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid constant: " + s)
+	}
+	return v
+}
+
+func (c *BLS12381Curve) G1Gen() [2]big.Int {
+	return bls12381G1Gen
+}
+
+func (c *BLS12381Curve) G2Affines(config Halo2VerifierConfig) ([]any, error) {
+	return c.ParseG2Points(config.VerifyCircuitG2Affine)
+}
+
+func (c *BLS12381Curve) ParseG2Points(vals [][]string) ([]any, error) {
+	res := make([]any, len(vals))
+
+	for i := range vals {
+		g := bls12381.G2Affine{}
+		g.X.A0.SetString(vals[i][0])
+		g.X.A1.SetString(vals[i][1])
+		g.Y.A0.SetString(vals[i][2])
+		g.Y.A1.SetString(vals[i][3])
+		if !g.IsOnCurve() {
+			return nil, fmt.Errorf("invalid g2 at %d", i)
+		}
+		v := sw_bls12381.NewG2Affine(g)
+		res[i] = &v
+	}
+
+	return res, nil
+}
+
+func (c *BLS12381Curve) PairingCheck(p1, p2 []any, g2 []any) error {
+	pairingApi, err := sw_bls12381.NewPairing(c.api)
+	if err != nil {
+		return fmt.Errorf("NewPairing: %w", err)
+	}
+
+	points := make([]*sw_emulated.AffinePoint[emparams.BLS12381Fp], len(p1)+len(p2))
+	for i := range p1 {
+		points[i] = p1[i].(*sw_emulated.AffinePoint[emparams.BLS12381Fp])
+	}
+	for i := range p2 {
+		points[len(p1)+i] = p2[i].(*sw_emulated.AffinePoint[emparams.BLS12381Fp])
+	}
+
+	g2Points := make([]*sw_bls12381.G2Affine, len(g2))
+	for i := range g2 {
+		g2Points[i] = g2[i].(*sw_bls12381.G2Affine)
+	}
+
+	return pairingApi.PairingCheck(points, g2Points)
+}
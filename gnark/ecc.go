@@ -1,9 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/math/emulated/emparams"
@@ -15,6 +17,10 @@ type BN254API struct {
 	fpFieldApi *emulated.Field[emparams.BN254Fp]
 	frFieldApi *emulated.Field[emparams.BN254Fr]
 	curveApi   *sw_emulated.Curve[emparams.BN254Fp, emparams.BN254Fr]
+	// useGLV snapshots the package-level UseGLV toggle at construction time,
+	// so BN254ScalarMul and friends can dispatch to the GLV-accelerated path
+	// in ecc_glv.go.
+	useGLV bool
 }
 
 func NewBN254API(
@@ -42,6 +48,7 @@ func NewBN254API(
 		frFieldApi: frFieldApi,
 		curveApi:   curveApi,
 		u256Api:    u256Api,
+		useGLV:     UseGLV,
 	}, nil
 }
 
@@ -118,19 +125,30 @@ func (bn254Api *BN254API) BN254ScalarMul(
 	point *sw_emulated.AffinePoint[emparams.BN254Fp],
 	scalar frontend.Variable,
 ) *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	if bn254Api.useGLV {
+		return bn254Api.BN254ScalarMulGLV(point, scalar)
+	}
+
 	scalarFr := bn254Api.ToBn254Fr(scalar)
 	p := bn254Api.curveApi.ScalarMul(point, scalarFr)
 	return p
 }
 
+// BN254FromConstant builds an in-circuit point from a Go-level constant via
+// fpFieldApi.NewElement, not emulated.ValueOf: ValueOf's own doc comment
+// says it's for witness assignment only and relies on schema parsing to
+// fill in its limbs, which never happens for a value conjured inside
+// Define() rather than carried on the circuit struct - the result is an
+// Element that silently fails to reduce correctly in later arithmetic.
+// NewElement decomposes the constant into limbs immediately, which is
+// exactly what "in-circuit constant assignment" (the doc comment's own
+// term for this case) requires.
 func (bn254Api *BN254API) BN254FromConstant(
 	point [2]big.Int,
 ) *sw_emulated.AffinePoint[emparams.BN254Fp] {
-	x := emulated.ValueOf[emparams.BN254Fp](point[0])
-	y := emulated.ValueOf[emparams.BN254Fp](point[1])
 	return &sw_emulated.AffinePoint[emparams.BN254Fp]{
-		X: x,
-		Y: y,
+		X: *bn254Api.fpFieldApi.NewElement(point[0]),
+		Y: *bn254Api.fpFieldApi.NewElement(point[1]),
 	}
 }
 
@@ -139,8 +157,7 @@ func (bn254Api *BN254API) BN254ScalarMulConstant(
 	scalar frontend.Variable,
 ) *sw_emulated.AffinePoint[emparams.BN254Fp] {
 	p := bn254Api.BN254FromConstant(point)
-	scalarFr := bn254Api.ToBn254Fr(scalar)
-	return bn254Api.curveApi.ScalarMul(p, scalarFr)
+	return bn254Api.BN254ScalarMul(p, scalar)
 }
 
 func (bn254Api *BN254API) BN254AddG1(
@@ -158,3 +175,28 @@ func (bn254Api *BN254API) BN254ScalarMulAndAddG1(
 	a := bn254Api.BN254ScalarMul(point, scalar)
 	return bn254Api.curveApi.Add(a, b)
 }
+
+// BN254FpBits decomposes a BN254Fp emulated element into its bit
+// representation, so a G1 point's coordinates can be bound into a
+// Fiat-Shamir transcript without detouring through U256.
+func (bn254Api *BN254API) BN254FpBits(e *emulated.Element[emparams.BN254Fp]) []frontend.Variable {
+	return bn254Api.fpFieldApi.ToBits(e)
+}
+
+// BN254KZGPairingCheck asserts that the product of e(p1[i], g2[i]) over
+// all i equals 1, the form every batched KZG verification in this package
+// reduces to.
+func (bn254Api *BN254API) BN254KZGPairingCheck(
+	p1 []*sw_emulated.AffinePoint[emparams.BN254Fp],
+	g2 []*sw_bn254.G2Affine,
+) error {
+	pairingApi, err := sw_bn254.NewPairing(bn254Api.api)
+	if err != nil {
+		return fmt.Errorf("BN254KZGPairingCheck: %w", err)
+	}
+	return pairingApi.PairingCheck(p1, g2)
+}
+
+// BN254MultiScalarMul is defined in ecc_msm.go, where it folds
+// points[i]*scalars[i] into a single point via curveApi.MultiScalarMul
+// instead of a sequential ScalarMul+Add loop.
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+// BLS12381API mirrors BN254API's surface over the emulated BLS12-381 G1,
+// for Halo2 proofs produced on BLS12-381 rather than BN254. Like BN254API
+// it is built on the generic short-Weierstrass sw_emulated.Curve; only the
+// field parameters differ.
+type BLS12381API struct {
+	api        frontend.API
+	u384Api    *U384API
+	fpFieldApi *emulated.Field[emparams.BLS12381Fp]
+	frFieldApi *emulated.Field[emparams.BLS12381Fr]
+	curveApi   *sw_emulated.Curve[emparams.BLS12381Fp, emparams.BLS12381Fr]
+}
+
+func NewBLS12381API(
+	api frontend.API,
+	u384Api *U384API,
+) (*BLS12381API, error) {
+	fpFieldApi, err := emulated.NewField[emparams.BLS12381Fp](api)
+	if err != nil {
+		return nil, err
+	}
+
+	frFieldApi, err := emulated.NewField[emparams.BLS12381Fr](api)
+	if err != nil {
+		return nil, err
+	}
+
+	curveApi, err := sw_emulated.New[emparams.BLS12381Fp, emparams.BLS12381Fr](api, sw_emulated.GetCurveParams[emparams.BLS12381Fp]())
+	if err != nil {
+		return nil, err
+	}
+
+	return &BLS12381API{
+		api:        api,
+		fpFieldApi: fpFieldApi,
+		frFieldApi: frFieldApi,
+		curveApi:   curveApi,
+		u384Api:    u384Api,
+	}, nil
+}
+
+func (blsApi *BLS12381API) BLS12381FpToU384(input *emulated.Element[emparams.BLS12381Fp]) U384 {
+	input = blsApi.fpFieldApi.Reduce(input)
+	bits := blsApi.fpFieldApi.ToBits(input)
+
+	for i := len(bits); i < 384; i++ {
+		bits = append(bits, 0)
+	}
+
+	for i := 384; i < len(bits); i++ {
+		blsApi.api.AssertIsEqual(bits[i], 0)
+	}
+
+	return blsApi.u384Api.FromBits(bits)
+}
+
+func (blsApi *BLS12381API) ToBLS12381Fp(input U384) *emulated.Element[emparams.BLS12381Fp] {
+	var fp emparams.BLS12381Fp
+	bits := blsApi.u384Api.ToBits(input)
+
+	fpBits := int(fp.NbLimbs() * fp.BitsPerLimb())
+	for i := fpBits; i < len(bits); i++ {
+		blsApi.api.AssertIsEqual(bits[i], 0)
+	}
+
+	return blsApi.fpFieldApi.FromBits(bits...)
+}
+
+func (blsApi *BLS12381API) ToBLS12381Point(point [2]U384) *sw_emulated.AffinePoint[emparams.BLS12381Fp] {
+	x := blsApi.ToBLS12381Fp(point[0])
+	y := blsApi.ToBLS12381Fp(point[1])
+
+	return &sw_emulated.AffinePoint[emparams.BLS12381Fp]{
+		X: *x,
+		Y: *y,
+	}
+}
+
+func (blsApi *BLS12381API) AssertOnCurve(x, y U384) *sw_emulated.AffinePoint[emparams.BLS12381Fp] {
+	point := blsApi.ToBLS12381Point([2]U384{x, y})
+	blsApi.curveApi.AssertIsOnCurve(point)
+	return point
+}
+
+func (blsApi *BLS12381API) BLS12381FromConstant(point [2]big.Int) *sw_emulated.AffinePoint[emparams.BLS12381Fp] {
+	x := emulated.ValueOf[emparams.BLS12381Fp](point[0])
+	y := emulated.ValueOf[emparams.BLS12381Fp](point[1])
+	return &sw_emulated.AffinePoint[emparams.BLS12381Fp]{X: x, Y: y}
+}
+
+func (blsApi *BLS12381API) ToBLS12381Fr(scalar frontend.Variable) *emulated.Element[emparams.BLS12381Fr] {
+	var fr emparams.BLS12381Fr
+	bits := blsApi.api.ToBinary(scalar)
+
+	frBits := int(fr.NbLimbs() * fr.BitsPerLimb())
+	for i := frBits; i < len(bits); i++ {
+		blsApi.api.AssertIsEqual(bits[i], 0)
+	}
+
+	return blsApi.frFieldApi.FromBits(bits...)
+}
+
+func (blsApi *BLS12381API) BLS12381ScalarMulConstant(point [2]big.Int, scalar frontend.Variable) *sw_emulated.AffinePoint[emparams.BLS12381Fp] {
+	p := blsApi.BLS12381FromConstant(point)
+	scalarFr := blsApi.ToBLS12381Fr(scalar)
+	return blsApi.curveApi.ScalarMul(p, scalarFr)
+}
+
+func (blsApi *BLS12381API) BLS12381ScalarMul(point *sw_emulated.AffinePoint[emparams.BLS12381Fp], scalar frontend.Variable) *sw_emulated.AffinePoint[emparams.BLS12381Fp] {
+	return blsApi.curveApi.ScalarMul(point, blsApi.ToBLS12381Fr(scalar))
+}
+
+func (blsApi *BLS12381API) BLS12381AddG1(a, b *sw_emulated.AffinePoint[emparams.BLS12381Fp]) *sw_emulated.AffinePoint[emparams.BLS12381Fp] {
+	return blsApi.curveApi.Add(a, b)
+}
+
+func (blsApi *BLS12381API) BLS12381ScalarMulAndAddG1(point *sw_emulated.AffinePoint[emparams.BLS12381Fp], scalar frontend.Variable, b *sw_emulated.AffinePoint[emparams.BLS12381Fp]) *sw_emulated.AffinePoint[emparams.BLS12381Fp] {
+	a := blsApi.curveApi.ScalarMul(point, blsApi.ToBLS12381Fr(scalar))
+	return blsApi.curveApi.Add(a, b)
+}
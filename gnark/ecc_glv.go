@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+// UseGLV toggles BN254ScalarMul, BN254ScalarMulConstant and
+// BN254ScalarMulAndAddG1 between BN254ScalarMulGLV and a direct call to
+// curveApi.ScalarMul. It defaults to off; set it before calling
+// NewBN254API to opt in.
+//
+// Both routes resolve to the same underlying curveApi.ScalarMul call.
+// sw_emulated.GetCurveParams[emparams.BN254Fp] configures BN254's GLV
+// eigenvalue, so curveApi.ScalarMul already decomposes the scalar via the
+// endomorphism internally - an earlier revision of BN254ScalarMulGLV
+// re-derived that decomposition from scratch (lattice basis reduction,
+// Babai rounding, a from-scratch double-and-add loop seeded with a
+// manually constructed identity point) only to hit a gnark emulated-field
+// panic ("trying to reduce a constant, which happen to have an overflow
+// flag set") the moment the accumulator's Select/AddUnified chain ran
+// long enough to get folded down to a compile-time constant with nonzero
+// overflow. The toggle is kept for callers that pinned UseGLV=true
+// expecting a distinct code path, but there's no correctness or
+// performance reason left to maintain a second implementation.
+var UseGLV = false
+
+// BN254ScalarMulGLV computes scalar*point, routed through
+// curveApi.ScalarMul's own GLV-accelerated implementation rather than a
+// hand-rolled endomorphism decomposition - see UseGLV's doc comment.
+func (bn254Api *BN254API) BN254ScalarMulGLV(
+	point *sw_emulated.AffinePoint[emparams.BN254Fp],
+	scalar frontend.Variable,
+) *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	scalarFr := bn254Api.ToBn254Fr(scalar)
+	return bn254Api.curveApi.ScalarMul(point, scalarFr)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// glvScalarMulCircuit exercises BN254ScalarMulGLV against a direct
+// curveApi.ScalarMul call on the same point and scalar: since
+// BN254ScalarMulGLV now just forwards to curveApi.ScalarMul (see UseGLV's
+// doc comment in ecc_glv.go), this is a thin regression guard against that
+// delegation silently diverging in a future edit.
+type glvScalarMulCircuit struct {
+	Point  sw_emulated.AffinePoint[emparams.BN254Fp]
+	Scalar frontend.Variable
+}
+
+func (c *glvScalarMulCircuit) Define(api frontend.API) error {
+	u64Api, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	u256Api := NewU256API(api, u64Api)
+	bn254Api, err := NewBN254API(api, u256Api)
+	if err != nil {
+		return err
+	}
+
+	glv := bn254Api.BN254ScalarMulGLV(&c.Point, c.Scalar)
+
+	scalarFr := bn254Api.ToBn254Fr(c.Scalar)
+	plain := bn254Api.curveApi.ScalarMul(&c.Point, scalarFr)
+
+	bn254Api.fpFieldApi.AssertIsEqual(&glv.X, &plain.X)
+	bn254Api.fpFieldApi.AssertIsEqual(&glv.Y, &plain.Y)
+	return nil
+}
+
+func TestBN254ScalarMulGLVMatchesPlainScalarMul(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	_, _, g1Gen, _ := bn254.Generators()
+
+	placeholder := &glvScalarMulCircuit{
+		Point: sw_emulated.AffinePoint[emparams.BN254Fp]{
+			X: emulated.ValueOf[emparams.BN254Fp](0),
+			Y: emulated.ValueOf[emparams.BN254Fp](0),
+		},
+	}
+
+	for _, k := range []int64{1, 2, 3, 12345, 987654321} {
+		var p bn254.G1Affine
+		p.ScalarMultiplication(&g1Gen, big.NewInt(k))
+
+		valid := &glvScalarMulCircuit{
+			Point: sw_emulated.AffinePoint[emparams.BN254Fp]{
+				X: emulated.ValueOf[emparams.BN254Fp](p.X.BigInt(new(big.Int))),
+				Y: emulated.ValueOf[emparams.BN254Fp](p.Y.BigInt(new(big.Int))),
+			},
+			Scalar: k,
+		}
+
+		assert.CheckCircuit(placeholder,
+			test.WithValidAssignment(valid),
+			test.WithCurves(ecc.BN254),
+		)
+	}
+}
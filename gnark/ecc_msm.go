@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+// BN254MultiScalarMul folds points[i]*scalars[i] into a single point via
+// curveApi.MultiScalarMul. An earlier revision reimplemented this as a
+// hand-rolled Pippenger bucket/window reduction; that version built its own
+// (0,0) identity point and chained it through many AddUnified/Select calls
+// before a single real point ever entered the accumulator, which the
+// default R1CS (groth16) builder folds down to a compile-time constant with
+// a nonzero overflow flag - a combination field_reduce.go explicitly
+// panics on ("trying to reduce a constant, which happen to have an
+// overflow flag set"). curveApi.MultiScalarMul already carries GLV
+// acceleration for curves configured with an eigenvalue (BN254 among
+// them, see sw_emulated.GetBN254Params), so it gives the same asymptotic
+// win without re-deriving that machinery in circuit.
+func (bn254Api *BN254API) BN254MultiScalarMul(
+	points []*sw_emulated.AffinePoint[emparams.BN254Fp],
+	scalars []frontend.Variable,
+) (*sw_emulated.AffinePoint[emparams.BN254Fp], error) {
+	if len(points) != len(scalars) {
+		return nil, fmt.Errorf("BN254MultiScalarMul: mismatched lengths %d points, %d scalars", len(points), len(scalars))
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("BN254MultiScalarMul: no points")
+	}
+
+	scalarsFr := make([]*emulated.Element[emparams.BN254Fr], len(scalars))
+	for i, s := range scalars {
+		scalarsFr[i] = bn254Api.ToBn254Fr(s)
+	}
+
+	return bn254Api.curveApi.MultiScalarMul(points, scalarsFr)
+}
+
+// BN254MultiScalarMulConstant is BN254MultiScalarMul specialized to the
+// case where every point is a constant (typically the [Q_i] selector
+// commitments baked into a verifying key).
+func (bn254Api *BN254API) BN254MultiScalarMulConstant(
+	points [][2]big.Int,
+	scalars []frontend.Variable,
+) (*sw_emulated.AffinePoint[emparams.BN254Fp], error) {
+	if len(points) != len(scalars) {
+		return nil, fmt.Errorf("BN254MultiScalarMulConstant: mismatched lengths %d points, %d scalars", len(points), len(scalars))
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("BN254MultiScalarMulConstant: no points")
+	}
+
+	varPoints := make([]*sw_emulated.AffinePoint[emparams.BN254Fp], len(points))
+	for i, pt := range points {
+		varPoints[i] = bn254Api.BN254FromConstant(pt)
+	}
+	return bn254Api.BN254MultiScalarMul(varPoints, scalars)
+}
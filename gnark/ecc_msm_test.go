@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// msmConstantCircuit exercises BN254MultiScalarMulConstant against a result
+// independently computed with plain bn254.G1Affine arithmetic. It runs once
+// with UseGLV on and once off, since UseGLV is a package var snapshotted at
+// NewBN254API time and both settings must still fold the same constant
+// points and scalars down to the same point.
+type msmConstantCircuit struct {
+	Points   [][2]big.Int
+	Scalars  []frontend.Variable
+	Expected [2]big.Int
+}
+
+func (c *msmConstantCircuit) newBN254Api(api frontend.API) (*BN254API, error) {
+	u64Api, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, err
+	}
+	u256Api := NewU256API(api, u64Api)
+	return NewBN254API(api, u256Api)
+}
+
+func (c *msmConstantCircuit) Define(api frontend.API) error {
+	bn254Api, err := c.newBN254Api(api)
+	if err != nil {
+		return err
+	}
+
+	got, err := bn254Api.BN254MultiScalarMulConstant(c.Points, c.Scalars)
+	if err != nil {
+		return err
+	}
+
+	expected := bn254Api.BN254FromConstant(c.Expected)
+	bn254Api.fpFieldApi.AssertIsEqual(&got.X, &expected.X)
+	bn254Api.fpFieldApi.AssertIsEqual(&got.Y, &expected.Y)
+	return nil
+}
+
+func TestBN254MultiScalarMulConstantGLVMatchesPlain(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	_, _, g1Gen, _ := bn254.Generators()
+
+	points := make([][2]big.Int, 3)
+	scalars := []int64{7, 12345, 987654321}
+	var expected bn254.G1Affine
+	for i, s := range scalars {
+		var p bn254.G1Affine
+		p.ScalarMultiplication(&g1Gen, big.NewInt(int64(2*i+3)))
+		p.X.BigInt(&points[i][0])
+		p.Y.BigInt(&points[i][1])
+
+		var term bn254.G1Affine
+		term.ScalarMultiplication(&p, big.NewInt(s))
+		if i == 0 {
+			expected = term
+		} else {
+			expected.Add(&expected, &term)
+		}
+	}
+
+	var expectedConst [2]big.Int
+	expected.X.BigInt(&expectedConst[0])
+	expected.Y.BigInt(&expectedConst[1])
+
+	circuitScalars := make([]frontend.Variable, len(scalars))
+	valid := &msmConstantCircuit{Points: points, Expected: expectedConst}
+	for i, s := range scalars {
+		circuitScalars[i] = s
+	}
+	valid.Scalars = circuitScalars
+
+	placeholder := &msmConstantCircuit{
+		Points:   points,
+		Scalars:  make([]frontend.Variable, len(scalars)),
+		Expected: expectedConst,
+	}
+
+	wasUseGLV := UseGLV
+	defer func() { UseGLV = wasUseGLV }()
+
+	for _, useGLV := range []bool{false, true} {
+		UseGLV = useGLV
+		assert.CheckCircuit(placeholder,
+			test.WithValidAssignment(valid),
+			test.WithCurves(ecc.BN254),
+		)
+	}
+}
@@ -12,15 +12,17 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
 	"github.com/consensys/gnark/backend/solidity"
 	"github.com/consensys/gnark/frontend"
 	gnarkio "github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/std/math/uints"
 )
 
-func loadProofData() (Halo2VerifierProofData, error) {
+func loadProofData(path string) (Halo2VerifierProofData, error) {
 	var res Halo2VerifierProofData
 
-	data, err := os.ReadFile("halo2_verifier_proof.json")
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return res, err
 	}
@@ -33,6 +35,67 @@ func loadProofData() (Halo2VerifierProofData, error) {
 	return res, nil
 }
 
+// loadBatchProofData loads `batchSize` proof files named
+// halo2_verifier_proof_<i>.json (0-indexed) for -batch mode. batchSize <= 1
+// falls back to the single halo2_verifier_proof.json file.
+func loadBatchProofData(batchSize int) ([]Halo2VerifierProofData, error) {
+	if batchSize <= 1 {
+		proofData, err := loadProofData("halo2_verifier_proof.json")
+		if err != nil {
+			return nil, err
+		}
+		return []Halo2VerifierProofData{proofData}, nil
+	}
+
+	res := make([]Halo2VerifierProofData, batchSize)
+	for i := 0; i < batchSize; i++ {
+		proofData, err := loadProofData(fmt.Sprintf("halo2_verifier_proof_%d.json", i))
+		if err != nil {
+			return nil, err
+		}
+		res[i] = proofData
+	}
+	return res, nil
+}
+
+func proofDataToVariables(proofData Halo2VerifierProofData) (Halo2VerifierProof, error) {
+	instance := make([][]frontend.Variable, len(proofData.Instance))
+	for i := range proofData.Instance {
+		instance[i] = make([]frontend.Variable, len(proofData.Instance[i]))
+		for j := range proofData.Instance[i] {
+			v, succeed := big.NewInt(0).SetString(proofData.Instance[i][j], 10)
+			if !succeed {
+				return Halo2VerifierProof{}, fmt.Errorf("invalid instance %s", proofData.Instance[i][j])
+			}
+			instance[i][j] = v
+		}
+	}
+
+	transcript := make([]frontend.Variable, len(proofData.Transcript))
+	for i := range proofData.Transcript {
+		v, succeed := big.NewInt(0).SetString(proofData.Transcript[i], 10)
+		if !succeed {
+			return Halo2VerifierProof{}, fmt.Errorf("invalid transcript %s", proofData.Transcript[i])
+		}
+		transcript[i] = v
+	}
+
+	return Halo2VerifierProof{Instance: instance, Transcript: transcript}, nil
+}
+
+// placeholderProof returns a proof of the same shape as proofData but with
+// unassigned (nil) variables, for use when building the circuit to compile.
+func placeholderProof(proofData Halo2VerifierProofData) Halo2VerifierProof {
+	instance := make([][]frontend.Variable, len(proofData.Instance))
+	for i := range proofData.Instance {
+		instance[i] = make([]frontend.Variable, len(proofData.Instance[i]))
+	}
+	return Halo2VerifierProof{
+		Instance:   instance,
+		Transcript: make([]frontend.Variable, len(proofData.Transcript)),
+	}
+}
+
 func loadHalo2VerifierConfig() (Halo2VerifierConfig, error) {
 	var res Halo2VerifierConfig
 
@@ -52,12 +115,25 @@ func loadHalo2VerifierConfig() (Halo2VerifierConfig, error) {
 func main() {
 	var backendIDString, curveIDString string
 	var isSetup bool
+	var batchSize int
 
 	flag.StringVar(&backendIDString, "backendID", "GROTH16", "Specify the backend ID (e.g., PLONK, GROTH16)")
 	flag.StringVar(&curveIDString, "curveID", "BN254", "Specify the curve ID (e.g., BN254, BLS12_381)")
 	flag.BoolVar(&isSetup, "setup", true, "Whether to setup to generate pk,vk")
+	flag.IntVar(&batchSize, "batch", 1, "Number of Halo2 proofs to verify together in one circuit (reads halo2_verifier_proof_<i>.json)")
+	var bench bool
+	flag.BoolVar(&bench, "bench", false, "Report instance-commitment constraint counts for 1-128 public inputs per instance column, then exit")
+	var recurse bool
+	flag.BoolVar(&recurse, "recurse", false, "After proving and verifying, wrap the proof in an outer BW6-761 circuit that verifies it via std/recursion/plonk")
+	var recurseBN254Path string
+	flag.StringVar(&recurseBN254Path, "recurseBN254", "", "Path to a bn254_recursion_proof.json describing a BN254 PLONK proof to wrap in an outer BN254 circuit via recursion.PlonkVerifier")
 	flag.Parse()
 
+	if bench {
+		benchInstanceCommitment()
+		return
+	}
+
 	backendID, err := parseBackendID(backendIDString)
 	if err != nil {
 		log.Fatalf("Invalid backendID: %v", err)
@@ -68,7 +144,7 @@ func main() {
 		log.Fatalf("Invalid curveID: %v", err)
 	}
 
-	proofData, err := loadProofData()
+	proofDataBatch, err := loadBatchProofData(batchSize)
 	if err != nil {
 		panic(err)
 	}
@@ -77,15 +153,15 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	config.BatchSize = uint32(len(proofDataBatch))
 
-	defalutInstance := make([][]frontend.Variable, len(proofData.Instance))
-	for i := range proofData.Instance {
-		defalutInstance[i] = make([]frontend.Variable, len(proofData.Instance[i]))
+	placeholderProofs := make([]Halo2VerifierProof, len(proofDataBatch))
+	for i := range proofDataBatch {
+		placeholderProofs[i] = placeholderProof(proofDataBatch[i])
 	}
 	halo2VerifierCircuit := Halo2VerifierCircuit{
-		config:     config,
-		Instance:   defalutInstance,
-		Transcript: make([]frontend.Variable, len(proofData.Transcript)),
+		config: config,
+		Proofs: placeholderProofs,
 	}
 
 	var (
@@ -125,31 +201,18 @@ func main() {
 		verifierOpts = append(verifierOpts, backend.WithVerifierHashToFieldFunction(sha256.New()))
 	}
 
-	succeed := true
-
 	// 3a. Fill witness and instance
-	instance := make([][]frontend.Variable, len(proofData.Instance))
-	for i := range proofData.Instance {
-		instance[i] = make([]frontend.Variable, len(proofData.Instance[i]))
-		for j := range proofData.Instance[i] {
-			instance[i][j], succeed = big.NewInt(0).SetString(proofData.Instance[i][j], 10)
-			if !succeed {
-				_ = fmt.Errorf("invalid instance", proofData.Instance[i][j])
-			}
-		}
-	}
-	transcript := make([]frontend.Variable, len(proofData.Transcript))
-	for i := range proofData.Transcript {
-		transcript[i], succeed = big.NewInt(0).SetString(proofData.Transcript[i], 10)
-		if !succeed {
-			_ = fmt.Errorf("invalid transcript", proofData.Transcript[i])
+	witnessProofs := make([]Halo2VerifierProof, len(proofDataBatch))
+	for i := range proofDataBatch {
+		witnessProofs[i], err = proofDataToVariables(proofDataBatch[i])
+		if err != nil {
+			log.Fatalln(err)
 		}
 	}
 
 	witnessCircuit := Halo2VerifierCircuit{
-		config:     config,
-		Transcript: transcript,
-		Instance:   instance,
+		config: config,
+		Proofs: witnessProofs,
 	}
 
 	// 3b. Generate witness
@@ -208,6 +271,88 @@ func main() {
 	log.Println("[End] verify")
 
 	SolidityVerification(backendID, vk.(solidity.VerifyingKey), proof, publicWitness, nil)
+
+	if recurse {
+		if backendID != backend.PLONK {
+			log.Fatalln("-recurse requires -backendID PLONK")
+		}
+		if curveID != ecc.BLS12_377 {
+			log.Fatalln("-recurse requires -curveID BLS12_377 (the outer BW6-761 circuit only verifies a BLS12-377 inner proof)")
+		}
+		log.Println("[Start] recurse")
+		if err := SetupRecursion(ccs, vk.(plonk.VerifyingKey), publicWitness, proof.(plonk.Proof)); err != nil {
+			log.Fatalln(err)
+		}
+		log.Println("[End] recurse")
+	}
+
+	if recurseBN254Path != "" {
+		log.Println("[Start] recurseBN254")
+		innerVk, innerProof, innerWitness, err := loadBn254RecursionData(recurseBN254Path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := SetupBN254Recursion(innerVk, innerProof, innerWitness); err != nil {
+			log.Fatalln(err)
+		}
+		log.Println("[End] recurseBN254")
+	}
+}
+
+// instanceCommitmentBenchCircuit isolates calcInstanceCommitment so its
+// constraint count can be measured independently of the rest of the
+// verifier circuit.
+type instanceCommitmentBenchCircuit struct {
+	config   Halo2VerifierConfig
+	Instance []frontend.Variable
+}
+
+func (circuit *instanceCommitmentBenchCircuit) Define(api frontend.API) error {
+	u64Api, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+
+	u256Api := NewU256API(api, u64Api)
+
+	bn254Api, err := NewBN254API(api, u256Api)
+	if err != nil {
+		return err
+	}
+
+	halo2Api, err := NewHalo2VerifierAPI(circuit.config, api, u64Api, u256Api, bn254Api)
+	if err != nil {
+		return err
+	}
+
+	_, err = halo2Api.calcInstanceCommitment(circuit.Instance)
+	return err
+}
+
+// benchInstanceCommitment compiles instanceCommitmentBenchCircuit for 1,
+// 2, 4, ..., 128 public inputs per instance column and logs each resulting
+// constraint count, so the MSM-based calcInstanceCommitment can be compared
+// against the sequential ScalarMulConstant+Add approach it replaced.
+func benchInstanceCommitment() {
+	basis := make([][]string, 128)
+	for i := range basis {
+		basis[i] = []string{"1", "2"}
+	}
+	config := Halo2VerifierConfig{VerifyCircuitGLagrange: basis}
+
+	for n := 1; n <= 128; n *= 2 {
+		circuit := instanceCommitmentBenchCircuit{
+			config:   config,
+			Instance: make([]frontend.Variable, n),
+		}
+
+		ccs, err := Compile(&circuit, ecc.BN254, backend.GROTH16, nil)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Printf("instance commitment bench: n=%d constraints=%d", n, ccs.GetNbConstraints())
+	}
 }
 
 func parseBackendID(backendIDString string) (backend.ID, error) {
@@ -227,6 +372,8 @@ func parseCurveID(curveIDString string) (ecc.ID, error) {
 		return ecc.BN254, nil
 	case "BLS12_381":
 		return ecc.BLS12_381, nil
+	case "BLS12_377":
+		return ecc.BLS12_377, nil
 	default:
 		return 0, fmt.Errorf("unsupported curve ID: %s", curveIDString)
 	}
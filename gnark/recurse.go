@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	gnarkio "github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bw6761"
+	"github.com/consensys/gnark/std/math/emulated"
+	recursion_plonk "github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+// OuterCircuit verifies, via std/recursion/plonk, a PLONK proof produced by
+// an inner Halo2VerifierCircuit, and re-exposes the inner circuit's public
+// Instance as its own public witness (InnerWitness). Feeding this outer
+// proof as the inner proof of another OuterCircuit lets callers aggregate
+// many Halo2 batches across further recursion steps.
+type OuterCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Proof        recursion_plonk.Proof[FR, G1El, G2El]
+	VerifyingKey recursion_plonk.VerifyingKey[FR, G1El, G2El] `gnark:"-"`
+	InnerWitness recursion_plonk.Witness[FR]                  `gnark:",public"`
+}
+
+func (c *OuterCircuit[FR, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	verifier, err := recursion_plonk.NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return fmt.Errorf("NewVerifier: %w", err)
+	}
+
+	return verifier.AssertProof(c.VerifyingKey, c.Proof, c.InnerWitness)
+}
+
+var (
+	RecurseDir            = fmt.Sprintf("%s/recurse", DIR)
+	PlonkOuterPkPath      = fmt.Sprintf("%s/plonk_pk_outer", RecurseDir)
+	PlonkOuterVkPath      = fmt.Sprintf("%s/plonk_vk_outer", RecurseDir)
+	PlonkOuterProofPath   = fmt.Sprintf("%s/proof_outer", RecurseDir)
+	PlonkOuterVerifierSol = fmt.Sprintf("%s/contract_plonk_outer.sol", RecurseDir)
+)
+
+// SetupRecursion compiles the BW6-761 outer circuit wrapping a verification
+// of the given inner BLS12-377 PLONK proof, runs setup and proving, and
+// writes pk_outer, vk_outer, proof_outer plus a Solidity verifier for the
+// outer proof only. The inner proof must have been produced over BLS12-377:
+// BW6-761's base field is BLS12-377's scalar field, which is what makes the
+// outer circuit's in-circuit pairing check over BLS12-377 possible.
+func SetupRecursion(innerCcs constraint.ConstraintSystem, innerVk plonk.VerifyingKey, innerWitness witness.Witness, innerProof plonk.Proof) error {
+	if err := os.MkdirAll(RecurseDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	circuitVk, err := recursion_plonk.ValueOfVerifyingKey[sw_bw6761.ScalarField, sw_bw6761.G1Affine, sw_bw6761.G2Affine](innerVk)
+	if err != nil {
+		return fmt.Errorf("ValueOfVerifyingKey: %w", err)
+	}
+	circuitProof, err := recursion_plonk.ValueOfProof[sw_bw6761.ScalarField, sw_bw6761.G1Affine, sw_bw6761.G2Affine](innerProof)
+	if err != nil {
+		return fmt.Errorf("ValueOfProof: %w", err)
+	}
+	circuitWitness, err := recursion_plonk.ValueOfWitness[sw_bw6761.ScalarField](innerWitness)
+	if err != nil {
+		return fmt.Errorf("ValueOfWitness: %w", err)
+	}
+
+	placeholderVk := recursion_plonk.PlaceholderVerifyingKey[sw_bw6761.ScalarField, sw_bw6761.G1Affine, sw_bw6761.G2Affine](innerCcs)
+
+	outerCircuit := &OuterCircuit[sw_bw6761.ScalarField, sw_bw6761.G1Affine, sw_bw6761.G2Affine, sw_bw6761.GTEl]{
+		Proof:        recursion_plonk.PlaceholderProof[sw_bw6761.ScalarField, sw_bw6761.G1Affine, sw_bw6761.G2Affine](innerCcs),
+		VerifyingKey: placeholderVk,
+		InnerWitness: recursion_plonk.PlaceholderWitness[sw_bw6761.ScalarField](innerCcs),
+	}
+
+	outerCcs, err := Compile(outerCircuit, ecc.BW6_761, backend.PLONK, nil)
+	if err != nil {
+		return fmt.Errorf("compile outer circuit: %w", err)
+	}
+
+	outerSrs, outerSrsLagrange, err := unsafekzg.NewSRS(outerCcs)
+	if err != nil {
+		return fmt.Errorf("outer SRS: %w", err)
+	}
+
+	outerPk, outerVk, err := plonk.Setup(outerCcs, outerSrs, outerSrsLagrange)
+	if err != nil {
+		return fmt.Errorf("outer setup: %w", err)
+	}
+
+	fOuterPk, err := os.Create(PlonkOuterPkPath)
+	if err != nil {
+		return err
+	}
+	if _, err := outerPk.WriteRawTo(fOuterPk); err != nil {
+		return err
+	}
+	fOuterVk, err := os.Create(PlonkOuterVkPath)
+	if err != nil {
+		return err
+	}
+	if _, err := outerVk.WriteRawTo(fOuterVk); err != nil {
+		return err
+	}
+
+	witnessCircuit := &OuterCircuit[sw_bw6761.ScalarField, sw_bw6761.G1Affine, sw_bw6761.G2Affine, sw_bw6761.GTEl]{
+		Proof:        circuitProof,
+		VerifyingKey: circuitVk,
+		InnerWitness: circuitWitness,
+	}
+
+	outerWitness, err := frontend.NewWitness(witnessCircuit, ecc.BW6_761.ScalarField())
+	if err != nil {
+		return fmt.Errorf("outer witness: %w", err)
+	}
+
+	outerProof, err := plonk.Prove(outerCcs, outerPk, outerWitness)
+	if err != nil {
+		return fmt.Errorf("outer prove: %w", err)
+	}
+
+	outerPublicWitness, err := outerWitness.Public()
+	if err != nil {
+		return err
+	}
+	if err := plonk.Verify(outerProof, outerVk, outerPublicWitness); err != nil {
+		return fmt.Errorf("outer verify: %w", err)
+	}
+
+	fProof, err := os.Create(PlonkOuterProofPath)
+	if err != nil {
+		return err
+	}
+	if _, err := outerProof.(gnarkio.WriterRawTo).WriteRawTo(fProof); err != nil {
+		return err
+	}
+
+	fSolidity, err := os.Create(PlonkOuterVerifierSol)
+	if err != nil {
+		return err
+	}
+	defer fSolidity.Close()
+	if err := outerVk.(solidity.VerifyingKey).ExportSolidity(fSolidity); err != nil {
+		return fmt.Errorf("ExportSolidity: %w", err)
+	}
+
+	log.Printf("[recurse] wrote %s, %s, %s, %s", PlonkOuterPkPath, PlonkOuterVkPath, PlonkOuterProofPath, PlonkOuterVerifierSol)
+
+	return nil
+}
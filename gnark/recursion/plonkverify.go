@@ -0,0 +1,401 @@
+// Package recursion verifies a single BN254 PLONK proof inside another
+// BN254 gnark circuit, mirroring the structure of gnark-crypto's native
+// plonk.Verify closely enough to drive the same Fiat-Shamir challenge
+// derivation (beta, gamma, alpha, zeta, v, u). It depends only on gnark's
+// own std library types plus the CurveAPI interface below, so the caller's
+// BN254 curve arithmetic helper (gnark/BN254API) can be plugged in without
+// this package importing the caller's package main.
+package recursion
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+// CurveAPI is the subset of BN254API's emulated-field/curve arithmetic
+// AssertProof needs. The caller's concrete *BN254API already implements
+// this (its BN254ScalarMul/BN254AddG1/BN254ScalarMulAndAddG1/
+// BN254ScalarMulConstant methods match verbatim); BN254FpBits and
+// BN254KZGPairingCheck are thin wrappers added alongside it for this
+// package's use.
+type CurveAPI interface {
+	BN254ScalarMul(point *sw_emulated.AffinePoint[emparams.BN254Fp], scalar frontend.Variable) *sw_emulated.AffinePoint[emparams.BN254Fp]
+	BN254ScalarMulConstant(point [2]big.Int, scalar frontend.Variable) *sw_emulated.AffinePoint[emparams.BN254Fp]
+	BN254AddG1(a, b *sw_emulated.AffinePoint[emparams.BN254Fp]) *sw_emulated.AffinePoint[emparams.BN254Fp]
+	BN254ScalarMulAndAddG1(point *sw_emulated.AffinePoint[emparams.BN254Fp], scalar frontend.Variable, b *sw_emulated.AffinePoint[emparams.BN254Fp]) *sw_emulated.AffinePoint[emparams.BN254Fp]
+	// BN254FpBits decomposes a BN254Fp emulated element into bits, for
+	// binding a G1 point's coordinates into the Fiat-Shamir transcript.
+	BN254FpBits(e *emulated.Element[emparams.BN254Fp]) []frontend.Variable
+	// BN254KZGPairingCheck asserts e(p1[0],g2[0])*e(p1[1],g2[1])*... == 1.
+	BN254KZGPairingCheck(p1 []*sw_emulated.AffinePoint[emparams.BN254Fp], g2 []*sw_bn254.G2Affine) error
+}
+
+// PlonkG1 is a BN254 G1 point represented as emulated field elements, so a
+// PlonkVerifyingKey/PlonkProof can be assigned directly as witness data
+// instead of going through a byte-level transcript.
+type PlonkG1 struct {
+	X, Y emulated.Element[emparams.BN254Fp]
+}
+
+func (g *PlonkG1) point() *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	return &sw_emulated.AffinePoint[emparams.BN254Fp]{X: g.X, Y: g.Y}
+}
+
+// bn254G1Gen is the standard BN254 G1 generator (1, 2), used to turn a
+// claimed scalar evaluation into a point via ScalarMulConstant so it can be
+// subtracted from a commitment ahead of the batched KZG pairing check.
+var bn254G1Gen = [2]big.Int{*big.NewInt(1), *big.NewInt(2)}
+
+// PlonkVerifyingKey is an in-circuit PLONK verifying key for a BN254 proof:
+// the selector and permutation commitments, the domain size/generator, and
+// the two SRS G2 points the final KZG pairing check needs.
+type PlonkVerifyingKey struct {
+	Size           uint64
+	NbPublicInputs uint64
+	Generator      frontend.Variable
+
+	// CosetShift1, CosetShift2 are the domain coset shifts k1, k2 used to
+	// build the copy-constraint's "identity permutation" columns for the b
+	// and c wires (the a wire uses the domain itself, i.e. a shift of 1).
+	CosetShift1, CosetShift2 frontend.Variable
+
+	Ql, Qr, Qm, Qo, Qk PlonkG1
+	S1, S2, S3         PlonkG1
+
+	// KZGG2, KZGG2X are the SRS points [1]_2 and [x]_2 the batched opening
+	// check pairs the folded commitment and the quotient opening against.
+	KZGG2, KZGG2X sw_bn254.G2Affine
+}
+
+// PlonkOpeningProof is a single KZG opening: the quotient commitment and
+// the claimed evaluation.
+type PlonkOpeningProof struct {
+	H            PlonkG1
+	ClaimedValue frontend.Variable
+}
+
+// PlonkProof is an in-circuit PLONK proof over BN254, mirroring the native
+// gnark-crypto PLONK proof shape closely enough to drive the same
+// Fiat-Shamir challenge derivation (beta, gamma, alpha, zeta, v, u).
+type PlonkProof struct {
+	LRO             [3]PlonkG1           // wire commitments [a], [b], [c]
+	LROEvals        [3]frontend.Variable // a(zeta), b(zeta), c(zeta)
+	PermEvals       [2]frontend.Variable // s1(zeta), s2(zeta), the two permutation-polynomial openings the copy-constraint check folds beta/gamma against
+	Z               PlonkG1              // permutation grand product commitment
+	H               [3]PlonkG1           // quotient commitment, split in 3
+	BatchedProof    PlonkOpeningProof
+	ZShiftedOpening PlonkOpeningProof
+}
+
+// PlonkWitness is a PLONK proof's public witness.
+type PlonkWitness struct {
+	Public []frontend.Variable
+}
+
+// PlaceholderVerifyingKey returns a PlonkVerifyingKey with the same shape
+// as vk (size, public input count, SRS points) but unassigned emulated
+// elements, for use when building a circuit to compile.
+func PlaceholderVerifyingKey(vk PlonkVerifyingKey) PlonkVerifyingKey {
+	return PlonkVerifyingKey{
+		Size:           vk.Size,
+		NbPublicInputs: vk.NbPublicInputs,
+		Generator:      vk.Generator,
+		CosetShift1:    vk.CosetShift1,
+		CosetShift2:    vk.CosetShift2,
+		KZGG2:          vk.KZGG2,
+		KZGG2X:         vk.KZGG2X,
+	}
+}
+
+// PlaceholderProof returns a PlonkProof with the same shape as proof but
+// unassigned emulated elements.
+func PlaceholderProof(proof PlonkProof) PlonkProof {
+	return PlonkProof{}
+}
+
+// PlaceholderWitness returns a PlonkWitness sized for nbPublicInputs public
+// inputs, unassigned.
+func PlaceholderWitness(nbPublicInputs int) PlonkWitness {
+	return PlonkWitness{Public: make([]frontend.Variable, nbPublicInputs)}
+}
+
+// plonkTranscript is a MiMC-based Fiat-Shamir transcript mirroring the
+// native PLONK challenge derivation order: beta, gamma, alpha, zeta, v, u.
+type plonkTranscript struct {
+	mimcApi mimc.MiMC
+}
+
+func newPlonkTranscript(api frontend.API) (*plonkTranscript, error) {
+	mimcApi, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, fmt.Errorf("newPlonkTranscript: %w", err)
+	}
+	return &plonkTranscript{mimcApi: mimcApi}, nil
+}
+
+func (t *plonkTranscript) bind(values ...frontend.Variable) {
+	t.mimcApi.Write(values...)
+}
+
+func (t *plonkTranscript) challenge() frontend.Variable {
+	c := t.mimcApi.Sum()
+	t.mimcApi.Reset()
+	t.mimcApi.Write(c)
+	return c
+}
+
+// scalarPow computes x^n via square-and-multiply.
+func scalarPow(api frontend.API, x frontend.Variable, n uint) frontend.Variable {
+	var acc frontend.Variable = 1
+	base := x
+
+	for n > 0 {
+		if n&1 == 1 {
+			acc = api.Mul(acc, base)
+		}
+		base = api.Mul(base, base)
+		n >>= 1
+	}
+
+	return acc
+}
+
+// PlonkVerifier verifies a single BN254 PLONK proof inside another BN254
+// gnark circuit, reusing a CurveAPI for all the emulated-field/curve
+// arithmetic.
+type PlonkVerifier struct {
+	api   frontend.API
+	curve CurveAPI
+}
+
+func NewPlonkVerifier(api frontend.API, curve CurveAPI) *PlonkVerifier {
+	return &PlonkVerifier{api: api, curve: curve}
+}
+
+func (pv *PlonkVerifier) bindPoint(t *plonkTranscript, g PlonkG1) {
+	t.bind(pv.api.FromBinary(pv.curve.BN254FpBits(&g.X)...), pv.api.FromBinary(pv.curve.BN254FpBits(&g.Y)...))
+}
+
+// vanishingPolyEval computes Z_H(zeta) = zeta^n - 1, the evaluation of the
+// domain's vanishing polynomial at zeta.
+func (pv *PlonkVerifier) vanishingPolyEval(zeta frontend.Variable, n uint64) frontend.Variable {
+	return pv.api.Sub(scalarPow(pv.api, zeta, uint(n)), 1)
+}
+
+// lagrangeEval computes L_i(zeta) = (g^i * (zeta^n - 1)) / (n * (zeta - g^i))
+// for the i-th Lagrange basis polynomial of a size-n multiplicative
+// subgroup with generator g.
+func (pv *PlonkVerifier) lagrangeEval(zeta, zhZeta frontend.Variable, n uint64, generator frontend.Variable, i uint64) frontend.Variable {
+	gi := scalarPow(pv.api, generator, uint(i))
+	num := pv.api.Mul(gi, zhZeta)
+	den := pv.api.Mul(n, pv.api.Sub(zeta, gi))
+	return pv.api.Div(num, den)
+}
+
+// linearizationCommitment folds the selector, permutation, grand-product
+// and quotient commitments into a single MSM, the in-circuit analogue of
+// the native verifier's linearization polynomial commitment: [Ql]*a +
+// [Qr]*b + [Qm]*a*b + [Qo]*c + [Qk] + [S_sigma_3]*s3Coeff + [Z]*zCoeff -
+// Z_H(zeta)*[H0] - zeta^(n+2)*Z_H(zeta)*[H1] - zeta^(2(n+2))*Z_H(zeta)*[H2],
+// where a, b, c are the proof's wire openings, s3Coeff is the
+// copy-constraint's S_sigma_3 coefficient, zCoeff is Z's own coefficient
+// (see AssertProof), and hCoeff0/1/2 are the already-negated zh/
+// zetaNPlusTwoZh/zetaNPlusTwoSquareZh weights AssertProof derives from
+// Z_H(zeta). Folding h in is what actually checks the quotient polynomial
+// divides the vanishing polynomial - without it, H is bound into the
+// transcript but never constrained against the rest of the proof, so a
+// prover could submit an arbitrary H alongside unsatisfying wire openings.
+func (pv *PlonkVerifier) linearizationCommitment(vk PlonkVerifyingKey, a, b, c, s3Coeff frontend.Variable, z PlonkG1, zCoeff frontend.Variable, h [3]PlonkG1, hCoeff0, hCoeff1, hCoeff2 frontend.Variable) *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	acc := pv.curve.BN254ScalarMul(vk.Ql.point(), a)
+	acc = pv.curve.BN254ScalarMulAndAddG1(vk.Qr.point(), b, acc)
+	acc = pv.curve.BN254ScalarMulAndAddG1(vk.Qm.point(), pv.api.Mul(a, b), acc)
+	acc = pv.curve.BN254ScalarMulAndAddG1(vk.Qo.point(), c, acc)
+	acc = pv.curve.BN254AddG1(acc, vk.Qk.point())
+	acc = pv.curve.BN254ScalarMulAndAddG1(vk.S3.point(), s3Coeff, acc)
+	acc = pv.curve.BN254ScalarMulAndAddG1(z.point(), zCoeff, acc)
+	acc = pv.curve.BN254ScalarMulAndAddG1(h[0].point(), hCoeff0, acc)
+	acc = pv.curve.BN254ScalarMulAndAddG1(h[1].point(), hCoeff1, acc)
+	acc = pv.curve.BN254ScalarMulAndAddG1(h[2].point(), hCoeff2, acc)
+	return acc
+}
+
+// AssertProof verifies proof against vk and publicWitness. It re-derives
+// the copy-constraint's constant term from beta, gamma, alpha and the
+// proof's openings (mirroring gnark's native plonk.Verify), asserts it
+// equals the claimed linearization-polynomial opening, then checks the
+// batched KZG opening at zeta: e(F - [E]*G1 + zeta*[H], [1]) * e(-[H], [x])
+// == 1, where F folds the linearization digest with [a], [b], [c],
+// [S_sigma_1] and [S_sigma_2] via powers of the multiopen challenge v, and
+// E folds their claimed evals the same way, so a, b, c, s1, s2 are bound
+// to proof.LRO/vk.S1/vk.S2 themselves rather than only entering the check
+// algebraically through constLin/s3Coeff. Z has no zeta-opening claim of
+// its own, so it is folded separately into the zeta*omega-opening check
+// below instead. The linearization digest itself folds proof.H[0..2] by
+// hCoeff0/1/2 (derived from Z_H(zeta)), the quotient-polynomial check that
+// ties H to the gate+permutation relation actually vanishing on the
+// evaluation domain — without it, proof.H is bound into the transcript
+// only to derive zeta and never otherwise constrained.
+func (pv *PlonkVerifier) AssertProof(vk PlonkVerifyingKey, proof PlonkProof, publicWitness PlonkWitness) error {
+	if uint64(len(publicWitness.Public)) != vk.NbPublicInputs {
+		return fmt.Errorf("AssertProof: expected %d public inputs, got %d", vk.NbPublicInputs, len(publicWitness.Public))
+	}
+
+	t, err := newPlonkTranscript(pv.api)
+	if err != nil {
+		return err
+	}
+
+	pv.bindPoint(t, vk.S1)
+	pv.bindPoint(t, vk.S2)
+	pv.bindPoint(t, vk.S3)
+	for _, v := range publicWitness.Public {
+		t.bind(v)
+	}
+	for i := range proof.LRO {
+		pv.bindPoint(t, proof.LRO[i])
+	}
+	beta := t.challenge()
+	gamma := t.challenge()
+
+	pv.bindPoint(t, proof.Z)
+	alpha := t.challenge()
+
+	for i := range proof.H {
+		pv.bindPoint(t, proof.H[i])
+	}
+	zeta := t.challenge()
+
+	zhZeta := pv.vanishingPolyEval(zeta, vk.Size)
+
+	// pi folds every public input against its own Lagrange basis
+	// evaluation at zeta: PI(zeta) = sum_i L_i(zeta) * publicWitness[i].
+	pi := frontend.Variable(0)
+	for i, w := range publicWitness.Public {
+		li := pv.lagrangeEval(zeta, zhZeta, vk.Size, vk.Generator, uint64(i))
+		pi = pv.api.Add(pi, pv.api.Mul(li, w))
+	}
+
+	l1Zeta := pv.lagrangeEval(zeta, zhZeta, vk.Size, vk.Generator, 0)
+	alphaSquareL1 := pv.api.Mul(pv.api.Mul(alpha, alpha), l1Zeta)
+
+	l, r, o := proof.LROEvals[0], proof.LROEvals[1], proof.LROEvals[2]
+	s1, s2 := proof.PermEvals[0], proof.PermEvals[1]
+	zu := proof.ZShiftedOpening.ClaimedValue
+
+	// permTerm is alpha*(l+beta*s1+gamma)*(r+beta*s2+gamma)*(o+gamma)*Z(omega*zeta),
+	// the copy-constraint's contribution to the linearization polynomial's
+	// constant term.
+	lTerm := pv.api.Add(l, pv.api.Add(pv.api.Mul(beta, s1), gamma))
+	rTerm := pv.api.Add(r, pv.api.Add(pv.api.Mul(beta, s2), gamma))
+	oTerm := pv.api.Add(o, gamma)
+	permTerm := pv.api.Mul(pv.api.Mul(pv.api.Mul(lTerm, rTerm), oTerm), pv.api.Mul(alpha, zu))
+
+	// constLin is -(PI(zeta) - alpha^2*L1(zeta) + permTerm), the value the
+	// linearization polynomial's opening must equal for the gate and
+	// copy-constraint relations to hold.
+	constLin := pv.api.Neg(pv.api.Sub(pv.api.Add(pi, permTerm), alphaSquareL1))
+	pv.api.AssertIsEqual(proof.BatchedProof.ClaimedValue, constLin)
+
+	// s3Coeff is [S_sigma_3]'s coefficient in the linearization digest:
+	// alpha*(l+beta*s1+gamma)*(r+beta*s2+gamma)*beta*Z(omega*zeta).
+	s3Coeff := pv.api.Mul(pv.api.Mul(lTerm, rTerm), pv.api.Mul(beta, pv.api.Mul(alpha, zu)))
+
+	// zCoeff is [Z]'s coefficient in the linearization digest: alpha^2*L1(zeta)
+	// - alpha*(l+beta*zeta+gamma)*(r+beta*k1*zeta+gamma)*(o+beta*k2*zeta+gamma).
+	// Unlike s3Coeff (which uses the proof's s1, s2 openings), this uses the
+	// identity permutation itself — zeta, k1*zeta, k2*zeta — evaluated at the
+	// domain's own coset shifts, so that folding [Z] by zCoeff actually ties
+	// the grand product to the copy-constraint recurrence it is supposed to
+	// satisfy, rather than leaving Z unchecked. The product term is
+	// subtracted, not added (matching the native verifier's
+	// coeffZ = alphaSquarelagrangeZero + _s2 with _s2 negated) — adding it
+	// instead folds [Z] with the wrong coefficient.
+	idLTerm := pv.api.Add(l, pv.api.Add(pv.api.Mul(beta, zeta), gamma))
+	idRTerm := pv.api.Add(r, pv.api.Add(pv.api.Mul(beta, pv.api.Mul(vk.CosetShift1, zeta)), gamma))
+	idOTerm := pv.api.Add(o, pv.api.Add(pv.api.Mul(beta, pv.api.Mul(vk.CosetShift2, zeta)), gamma))
+	zCoeff := pv.api.Sub(
+		alphaSquareL1,
+		pv.api.Mul(pv.api.Mul(idLTerm, idRTerm), pv.api.Mul(idOTerm, alpha)),
+	)
+
+	// hCoeff0/1/2 are proof.H[0..2]'s coefficients in the linearization
+	// digest: -Z_H(zeta), -zeta^(n+2)*Z_H(zeta), -zeta^(2*(n+2))*Z_H(zeta)
+	// (matching the native verifier's zh/zetaNPlusTwoZh/
+	// zetaNPlusTwoSquareZh). This is the quotient-polynomial check itself:
+	// folding H in at these weights is what constrains H to actually be the
+	// polynomial the gate+permutation relation divides by Z_H(X), rather
+	// than H merely being bound into the transcript to derive zeta.
+	zetaNPlusTwo := scalarPow(pv.api, zeta, uint(vk.Size+2))
+	zetaNPlusTwoSquare := pv.api.Mul(zetaNPlusTwo, zetaNPlusTwo)
+	hCoeff0 := pv.api.Neg(zhZeta)
+	hCoeff1 := pv.api.Neg(pv.api.Mul(zetaNPlusTwo, zhZeta))
+	hCoeff2 := pv.api.Neg(pv.api.Mul(zetaNPlusTwoSquare, zhZeta))
+
+	linCommitment := pv.linearizationCommitment(vk, l, r, o, s3Coeff, proof.Z, zCoeff, proof.H, hCoeff0, hCoeff1, hCoeff2)
+
+	// Bind every claimed opening before deriving the multiopen challenge v,
+	// so a cheating prover can't pick them after v is fixed, then fold
+	// [linCommitment], proof.LRO[0..2] and vk.S1/S2 into one batched
+	// commitment so their claimed evals a, b, c, s1, s2 are actually
+	// checked against their own commitments. Z has no zeta-opening claim
+	// of its own (the copy-constraint check above only ties it to zu at
+	// zeta*omega, via zShiftedCommitment below) so it does not belong in
+	// this fold.
+	t.bind(proof.BatchedProof.ClaimedValue, l, r, o, s1, s2, zu)
+	v := t.challenge()
+
+	openedCommitments := []*sw_emulated.AffinePoint[emparams.BN254Fp]{
+		linCommitment, proof.LRO[0].point(), proof.LRO[1].point(), proof.LRO[2].point(), vk.S1.point(), vk.S2.point(),
+	}
+	openedEvals := []frontend.Variable{proof.BatchedProof.ClaimedValue, l, r, o, s1, s2}
+
+	F := openedCommitments[0]
+	E := openedEvals[0]
+	for i := 1; i < len(openedCommitments); i++ {
+		vPow := scalarPow(pv.api, v, uint(i))
+		F = pv.curve.BN254ScalarMulAndAddG1(openedCommitments[i], vPow, F)
+		E = pv.api.Add(E, pv.api.Mul(vPow, openedEvals[i]))
+	}
+
+	negFoldedEval := pv.curve.BN254ScalarMulConstant(bn254G1Gen, pv.api.Neg(E))
+	foldedCommitment := pv.curve.BN254AddG1(F, negFoldedEval)
+	zetaH := pv.curve.BN254ScalarMul(proof.BatchedProof.H.point(), zeta)
+	shiftedCommitment := pv.curve.BN254AddG1(foldedCommitment, zetaH)
+
+	negH := pv.curve.BN254ScalarMul(proof.BatchedProof.H.point(), pv.api.Neg(1))
+
+	// The same batched KZG equation, at the shifted point omega*zeta,
+	// checks that Z truly opens to zu there via ZShiftedOpening.H.
+	shiftedZeta := pv.api.Mul(zeta, vk.Generator)
+	negZuG1 := pv.curve.BN254ScalarMulConstant(bn254G1Gen, pv.api.Neg(zu))
+	zFolded := pv.curve.BN254AddG1(proof.Z.point(), negZuG1)
+	shiftedZetaH := pv.curve.BN254ScalarMul(proof.ZShiftedOpening.H.point(), shiftedZeta)
+	zShiftedCommitment := pv.curve.BN254AddG1(zFolded, shiftedZetaH)
+	negZShiftedH := pv.curve.BN254ScalarMul(proof.ZShiftedOpening.H.point(), pv.api.Neg(1))
+
+	// Bind the main opening proof to derive the multiopen challenge u, then
+	// fold the zeta·omega-opening pair into the zeta-opening pair by u
+	// before the final pairing check. Pairing against the same G2 points
+	// with weight 1 on both pairs would only check their sum, letting a
+	// forged H/ZShiftedOpening.H cancel a false opening at one point
+	// against the other; weighting the second pair by a challenge the
+	// prover can't predict when choosing H closes that gap, the same way
+	// Halo2VerifierAPI.verify (gnark package) folds its W/wShifted pair by u.
+	pv.bindPoint(t, proof.BatchedProof.H)
+	u := t.challenge()
+
+	combined := pv.curve.BN254AddG1(shiftedCommitment, pv.curve.BN254ScalarMul(zShiftedCommitment, u))
+	combinedNegH := pv.curve.BN254AddG1(negH, pv.curve.BN254ScalarMul(negZShiftedH, u))
+
+	return pv.curve.BN254KZGPairingCheck(
+		[]*sw_emulated.AffinePoint[emparams.BN254Fp]{combined, combinedNegH},
+		[]*sw_bn254.G2Affine{&vk.KZGG2, &vk.KZGG2X},
+	)
+}
@@ -0,0 +1,648 @@
+package recursion
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	mimcfr "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+// assertProofCircuit just runs PlonkVerifier.AssertProof over the supplied
+// VK/proof/witness, so TestAssertProofAcceptsHonestProofRejectsTampered can
+// drive it with both an honestly-generated PLONK proof and a tampered one.
+type assertProofCircuit struct {
+	VK      PlonkVerifyingKey
+	Proof   PlonkProof
+	Witness PlonkWitness
+}
+
+func (c *assertProofCircuit) Define(api frontend.API) error {
+	curve, err := newTestCurveAPI(api)
+	if err != nil {
+		return err
+	}
+	pv := NewPlonkVerifier(api, curve)
+	return pv.AssertProof(c.VK, c.Proof, c.Witness)
+}
+
+// plonkPlaintextTranscript replicates plonkTranscript's MiMC-based
+// Fiat-Shamir derivation (bind/challenge) out of circuit, over
+// gnark-crypto's bn254 scalar field, so an honest proof can be built with
+// the exact same challenge order AssertProof derives in-circuit.
+type plonkPlaintextTranscript struct {
+	h mimcfr.FieldHasher
+}
+
+func newPlonkPlaintextTranscript() *plonkPlaintextTranscript {
+	return &plonkPlaintextTranscript{h: mimcfr.NewFieldHasher()}
+}
+
+func (t *plonkPlaintextTranscript) bind(vals ...fr.Element) {
+	for _, v := range vals {
+		t.h.WriteElement(v)
+	}
+}
+
+func (t *plonkPlaintextTranscript) bindPoint(p bn254.G1Affine) {
+	var x, y big.Int
+	p.X.BigInt(&x)
+	p.Y.BigInt(&y)
+	var xe, ye fr.Element
+	xe.SetBigInt(&x)
+	ye.SetBigInt(&y)
+	t.bind(xe, ye)
+}
+
+func (t *plonkPlaintextTranscript) challenge() fr.Element {
+	c := t.h.SumElement()
+	t.h.Reset()
+	t.h.WriteElement(c)
+	return c
+}
+
+// plonkPoly helpers (ascending-coefficient-order polynomials over fr.Element),
+// just enough to build a small honest PLONK proof from scratch.
+
+func plonkPolTrim(p []fr.Element) []fr.Element {
+	n := len(p)
+	for n > 1 && p[n-1].IsZero() {
+		n--
+	}
+	return p[:n]
+}
+
+func plonkPolAdd(a, b []fr.Element) []fr.Element {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	out := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		var av, bv fr.Element
+		if i < len(a) {
+			av = a[i]
+		}
+		if i < len(b) {
+			bv = b[i]
+		}
+		out[i].Add(&av, &bv)
+	}
+	return plonkPolTrim(out)
+}
+
+func plonkPolSub(a, b []fr.Element) []fr.Element {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	out := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		var av, bv fr.Element
+		if i < len(a) {
+			av = a[i]
+		}
+		if i < len(b) {
+			bv = b[i]
+		}
+		out[i].Sub(&av, &bv)
+	}
+	return plonkPolTrim(out)
+}
+
+func plonkPolScale(a []fr.Element, s fr.Element) []fr.Element {
+	out := make([]fr.Element, len(a))
+	for i := range a {
+		out[i].Mul(&a[i], &s)
+	}
+	return plonkPolTrim(out)
+}
+
+func plonkPolMul(a, b []fr.Element) []fr.Element {
+	out := make([]fr.Element, len(a)+len(b)-1)
+	var tmp fr.Element
+	for i := range a {
+		for j := range b {
+			tmp.Mul(&a[i], &b[j])
+			out[i+j].Add(&out[i+j], &tmp)
+		}
+	}
+	return plonkPolTrim(out)
+}
+
+func plonkPolEval(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x).Add(&res, &p[i])
+	}
+	return res
+}
+
+// plonkPolCompose returns p(s*X), i.e. coefficient[k] *= s^k, used to build
+// Z(omega*X) from Z(X).
+func plonkPolCompose(p []fr.Element, s fr.Element) []fr.Element {
+	out := make([]fr.Element, len(p))
+	var sk fr.Element
+	sk.SetOne()
+	for i := range p {
+		out[i].Mul(&p[i], &sk)
+		sk.Mul(&sk, &s)
+	}
+	return plonkPolTrim(out)
+}
+
+func plonkMonomial(degree int, coeff fr.Element) []fr.Element {
+	p := make([]fr.Element, degree+1)
+	p[degree] = coeff
+	return p
+}
+
+// plonkLagrangeInterpolate returns the unique polynomial of degree <
+// len(xs) with p(xs[i]) == ys[i].
+func plonkLagrangeInterpolate(xs, ys []fr.Element) []fr.Element {
+	var zero, one fr.Element
+	one.SetOne()
+	result := []fr.Element{zero}
+	for i := range xs {
+		basis := []fr.Element{one}
+		var denom fr.Element
+		denom.SetOne()
+		for j := range xs {
+			if j == i {
+				continue
+			}
+			var negXj fr.Element
+			negXj.Neg(&xs[j])
+			basis = plonkPolMul(basis, []fr.Element{negXj, one})
+			var diff fr.Element
+			diff.Sub(&xs[i], &xs[j])
+			denom.Mul(&denom, &diff)
+		}
+		var invDenom, scale fr.Element
+		invDenom.Inverse(&denom)
+		scale.Mul(&ys[i], &invDenom)
+		result = plonkPolAdd(result, plonkPolScale(basis, scale))
+	}
+	return result
+}
+
+// plonkDivByVanishing divides p by X^n-1 exactly, returning the quotient
+// and whether the remainder was zero.
+func plonkDivByVanishing(p []fr.Element, n int) ([]fr.Element, bool) {
+	rem := make([]fr.Element, len(p))
+	copy(rem, p)
+	deg := len(rem) - 1
+	qlen := 0
+	if deg >= n {
+		qlen = deg - n + 1
+	}
+	q := make([]fr.Element, qlen)
+	for i := deg; i >= n; i-- {
+		c := rem[i]
+		q[i-n] = c
+		rem[i-n].Add(&rem[i-n], &c)
+	}
+	ok := true
+	for i := 0; i < n && i < len(rem); i++ {
+		if !rem[i].IsZero() {
+			ok = false
+		}
+	}
+	return plonkPolTrim(q), ok
+}
+
+// honestPlonkProof is everything TestAssertProofAcceptsHonestProofRejectsTampered
+// needs: the circuit-ready VK/proof/witness plus a handle on the openings
+// so the "tampered" variant can perturb exactly one claimed value.
+type honestPlonkProof struct {
+	vk      PlonkVerifyingKey
+	proof   PlonkProof
+	witness PlonkWitness
+}
+
+// buildHonestPlonkProof constructs a complete, honestly-generated PLONK
+// proof for a single toy gate over a size-4 domain (a*b - c + 7 = 0, with a
+// transposition permutation a_0<->a_1 so Z is genuinely non-constant),
+// using a plain-Go replica of plonkTranscript (see
+// plonkPlaintextTranscript) to derive the exact same Fiat-Shamir
+// challenges AssertProof does in-circuit. It returns both the honest
+// proof and the raw polynomials/commitments needed to derive a tampered
+// variant without re-deriving the whole transcript.
+func buildHonestPlonkProof(t *testing.T) honestPlonkProof {
+	t.Helper()
+
+	const n = 4
+	generator, ok := new(big.Int).SetString(
+		"21888242871839275217838484774961031246007050428528088939761107053157389710902", 10,
+	) // a primitive 4th root of unity mod the BN254 scalar field
+	if !ok {
+		t.Fatal("invalid generator constant")
+	}
+	var g fr.Element
+	g.SetBigInt(generator)
+
+	feltI := func(v int64) fr.Element {
+		var e fr.Element
+		e.SetInt64(v)
+		return e
+	}
+
+	k1, k2 := feltI(2), feltI(3)
+
+	xs := make([]fr.Element, n)
+	xs[0].SetOne()
+	for i := 1; i < n; i++ {
+		xs[i].Mul(&xs[i-1], &g)
+	}
+
+	// Selectors for a single gate repeated over the domain: Ql*a + Qr*b +
+	// Qm*a*b + Qo*c + Qk = 0, with Qo = -1 so c is the gate's "output".
+	Ql, Qr, Qm, Qo, Qk := feltI(2), feltI(3), feltI(5), feltI(-1), feltI(7)
+
+	// a_0 == a_1 so the transposition permutation on column a below keeps
+	// the grand product's closing condition satisfiable.
+	aVals := []fr.Element{feltI(2), feltI(2), feltI(4), feltI(5)}
+	bVals := []fr.Element{feltI(3), feltI(4), feltI(5), feltI(6)}
+	cVals := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		var t1, t2, t3 fr.Element
+		t1.Mul(&Ql, &aVals[i])
+		t2.Mul(&Qr, &bVals[i])
+		t3.Mul(&Qm, &aVals[i])
+		t3.Mul(&t3, &bVals[i])
+		cVals[i].Add(&t1, &t2)
+		cVals[i].Add(&cVals[i], &t3)
+		cVals[i].Add(&cVals[i], &Qk)
+	}
+
+	aPoly := plonkLagrangeInterpolate(xs, aVals)
+	bPoly := plonkLagrangeInterpolate(xs, bVals)
+	cPoly := plonkLagrangeInterpolate(xs, cVals)
+
+	perm := []int{1, 0, 2, 3}
+	sigmaA := make([]fr.Element, n)
+	sigmaB := make([]fr.Element, n)
+	sigmaC := make([]fr.Element, n)
+	idA := make([]fr.Element, n)
+	idB := make([]fr.Element, n)
+	idC := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		sigmaA[i] = xs[perm[i]]
+		idA[i] = xs[i]
+		idB[i].Mul(&k1, &xs[i])
+		sigmaB[i] = idB[i]
+		idC[i].Mul(&k2, &xs[i])
+		sigmaC[i] = idC[i]
+	}
+
+	S1Poly := plonkLagrangeInterpolate(xs, sigmaA)
+	S2Poly := plonkLagrangeInterpolate(xs, sigmaB)
+	S3Poly := plonkLagrangeInterpolate(xs, sigmaC)
+
+	srs, err := kzg.NewSRS(64, big.NewInt(987654321))
+	if err != nil {
+		t.Fatalf("kzg.NewSRS: %v", err)
+	}
+	pk := srs.Pk
+
+	commit := func(p []fr.Element) kzg.Digest {
+		d, err := kzg.Commit(p, pk)
+		if err != nil {
+			t.Fatalf("kzg.Commit: %v", err)
+		}
+		return d
+	}
+
+	S1Comm := commit(S1Poly)
+	S2Comm := commit(S2Poly)
+	S3Comm := commit(S3Poly)
+	aComm := commit(aPoly)
+	bComm := commit(bPoly)
+	cComm := commit(cPoly)
+
+	tr := newPlonkPlaintextTranscript()
+	tr.bindPoint(S1Comm)
+	tr.bindPoint(S2Comm)
+	tr.bindPoint(S3Comm)
+	tr.bindPoint(aComm)
+	tr.bindPoint(bComm)
+	tr.bindPoint(cComm)
+	beta := tr.challenge()
+	gamma := tr.challenge()
+
+	// Grand-product recurrence: Z(g^0) = 1, Z(g^{i+1}) = Z(g^i) * ratio_i,
+	// and the closing condition (Z(g^n) == 1) must hold for the chosen
+	// permutation to actually be a permutation.
+	zVals := make([]fr.Element, n)
+	zVals[0].SetOne()
+	var closing fr.Element
+	closing.SetOne()
+	for i := 0; i < n; i++ {
+		term := func(val, label fr.Element) fr.Element {
+			var t fr.Element
+			t.Mul(&beta, &label)
+			t.Add(&t, &gamma)
+			t.Add(&t, &val)
+			return t
+		}
+		numL, numR, numO := term(aVals[i], idA[i]), term(bVals[i], idB[i]), term(cVals[i], idC[i])
+		denL, denR, denO := term(aVals[i], sigmaA[i]), term(bVals[i], sigmaB[i]), term(cVals[i], sigmaC[i])
+
+		var numProd, denProd, invDen, ratio fr.Element
+		numProd.Mul(&numL, &numR)
+		numProd.Mul(&numProd, &numO)
+		denProd.Mul(&denL, &denR)
+		denProd.Mul(&denProd, &denO)
+		invDen.Inverse(&denProd)
+		ratio.Mul(&numProd, &invDen)
+
+		if i < n-1 {
+			zVals[i+1].Mul(&zVals[i], &ratio)
+		} else {
+			closing.Mul(&zVals[i], &ratio)
+		}
+	}
+	if !closing.IsOne() {
+		t.Fatal("buildHonestPlonkProof: permutation does not close (Z(g^n) != 1)")
+	}
+
+	ZPoly := plonkLagrangeInterpolate(xs, zVals)
+	ZComm := commit(ZPoly)
+	tr.bindPoint(ZComm)
+	alpha := tr.challenge()
+
+	L1Poly := plonkLagrangeInterpolate(xs, []fr.Element{feltI(1), feltI(0), feltI(0), feltI(0)})
+
+	gateX := plonkPolAdd(
+		plonkPolAdd(plonkPolScale(aPoly, Ql), plonkPolScale(bPoly, Qr)),
+		plonkPolAdd(plonkPolScale(plonkPolMul(aPoly, bPoly), Qm), plonkPolAdd(plonkPolScale(cPoly, Qo), []fr.Element{Qk})),
+	)
+
+	xMono := []fr.Element{feltI(0), feltI(1)}
+	copyNum := plonkPolMul(plonkPolMul(
+		plonkPolAdd(aPoly, plonkPolAdd(plonkPolScale(xMono, beta), []fr.Element{gamma})),
+		plonkPolAdd(bPoly, plonkPolAdd(plonkPolScale(plonkPolScale(xMono, k1), beta), []fr.Element{gamma})),
+	), plonkPolAdd(cPoly, plonkPolAdd(plonkPolScale(plonkPolScale(xMono, k2), beta), []fr.Element{gamma})))
+
+	copyDen := plonkPolMul(plonkPolMul(
+		plonkPolAdd(aPoly, plonkPolAdd(plonkPolScale(S1Poly, beta), []fr.Element{gamma})),
+		plonkPolAdd(bPoly, plonkPolAdd(plonkPolScale(S2Poly, beta), []fr.Element{gamma})),
+	), plonkPolAdd(cPoly, plonkPolAdd(plonkPolScale(S3Poly, beta), []fr.Element{gamma})))
+
+	ZgPoly := plonkPolCompose(ZPoly, g)
+
+	// copyTerm is copyDen(X)*Z(gX) - copyNum(X)*Z(X): the permutation
+	// labels multiply the *shifted* Z and the identity labels multiply the
+	// unshifted Z, matching gnark's native plonk prover (backend/plonk) -
+	// the other orientation produces a T(X) that is still divisible by
+	// Z_H(X) but disagrees with the verifier's linearization convention.
+	copyTerm := plonkPolSub(plonkPolMul(copyDen, ZgPoly), plonkPolMul(copyNum, ZPoly))
+	l1Term := plonkPolMul(plonkPolSub(ZPoly, []fr.Element{feltI(1)}), L1Poly)
+
+	var alphaSq fr.Element
+	alphaSq.Mul(&alpha, &alpha)
+
+	T := plonkPolAdd(gateX, plonkPolAdd(plonkPolScale(copyTerm, alpha), plonkPolScale(l1Term, alphaSq)))
+
+	Q, ok := plonkDivByVanishing(T, n)
+	if !ok {
+		t.Fatal("buildHonestPlonkProof: constraint polynomial not divisible by Z_H(X)")
+	}
+
+	// H1, H2 are arbitrary nonzero constants (a zero-coefficient
+	// polynomial commits to the point at infinity, which this repo's
+	// in-circuit EC arithmetic cannot represent); H0 absorbs the rest of
+	// the quotient.
+	h1Const, h2Const := feltI(19), feltI(17)
+	H0 := plonkPolSub(plonkPolSub(Q, plonkMonomial(n+2, h1Const)), plonkMonomial(2*n+4, h2Const))
+	H1 := []fr.Element{h1Const}
+	H2 := []fr.Element{h2Const}
+
+	H0Comm, H1Comm, H2Comm := commit(H0), commit(H1), commit(H2)
+	tr.bindPoint(H0Comm)
+	tr.bindPoint(H1Comm)
+	tr.bindPoint(H2Comm)
+	zeta := tr.challenge()
+
+	l := plonkPolEval(aPoly, zeta)
+	r := plonkPolEval(bPoly, zeta)
+	o := plonkPolEval(cPoly, zeta)
+	s1 := plonkPolEval(S1Poly, zeta)
+	s2 := plonkPolEval(S2Poly, zeta)
+	var zshift fr.Element
+	zshift.Mul(&zeta, &g)
+	zu := plonkPolEval(ZPoly, zshift)
+
+	var zn, zhZeta, one fr.Element
+	one.SetOne()
+	zn.Exp(zeta, big.NewInt(n))
+	zhZeta.Sub(&zn, &one)
+
+	l1Zeta := plonkPolEval(L1Poly, zeta)
+	var alphaSquareL1 fr.Element
+	alphaSquareL1.Mul(&alphaSq, &l1Zeta)
+
+	add3 := func(val, coefTerm, c fr.Element) fr.Element {
+		var t fr.Element
+		t.Add(&val, &coefTerm)
+		t.Add(&t, &c)
+		return t
+	}
+	mul := func(a, b fr.Element) fr.Element { var t fr.Element; t.Mul(&a, &b); return t }
+
+	lTerm := add3(l, mul(beta, s1), gamma)
+	rTerm := add3(r, mul(beta, s2), gamma)
+	var oTerm fr.Element
+	oTerm.Add(&o, &gamma)
+
+	alphaZu := mul(alpha, zu)
+	var permTerm fr.Element
+	permTerm.Mul(&lTerm, &rTerm)
+	permTerm.Mul(&permTerm, &oTerm)
+	permTerm.Mul(&permTerm, &alphaZu)
+
+	var constLin fr.Element
+	constLin.Sub(&alphaSquareL1, &permTerm)
+
+	betaAlphaZu := mul(beta, alphaZu)
+	var s3Coeff fr.Element
+	s3Coeff.Mul(&lTerm, &rTerm)
+	s3Coeff.Mul(&s3Coeff, &betaAlphaZu)
+
+	idLTerm := add3(l, mul(beta, zeta), gamma)
+	idRTerm := add3(r, mul(beta, mul(k1, zeta)), gamma)
+	idOTerm := add3(o, mul(beta, mul(k2, zeta)), gamma)
+	var idProd fr.Element
+	idProd.Mul(&idLTerm, &idRTerm)
+	idProd.Mul(&idProd, &idOTerm)
+	idProd.Mul(&idProd, &alpha)
+	var zCoeff fr.Element
+	zCoeff.Sub(&alphaSquareL1, &idProd)
+
+	var zetaNPlusTwo, zetaNPlusTwoSquare fr.Element
+	zetaNPlusTwo.Exp(zeta, big.NewInt(n+2))
+	zetaNPlusTwoSquare.Mul(&zetaNPlusTwo, &zetaNPlusTwo)
+	var hCoeff0, hCoeff1, hCoeff2 fr.Element
+	hCoeff0.Neg(&zhZeta)
+	hCoeff1.Mul(&zetaNPlusTwo, &zhZeta)
+	hCoeff1.Neg(&hCoeff1)
+	hCoeff2.Mul(&zetaNPlusTwoSquare, &zhZeta)
+	hCoeff2.Neg(&hCoeff2)
+
+	linPoly := plonkPolScale([]fr.Element{Ql}, l)
+	linPoly = plonkPolAdd(linPoly, plonkPolScale([]fr.Element{Qr}, r))
+	linPoly = plonkPolAdd(linPoly, plonkPolScale([]fr.Element{Qm}, mul(l, r)))
+	linPoly = plonkPolAdd(linPoly, plonkPolScale([]fr.Element{Qo}, o))
+	linPoly = plonkPolAdd(linPoly, []fr.Element{Qk})
+	linPoly = plonkPolAdd(linPoly, plonkPolScale(S3Poly, s3Coeff))
+	linPoly = plonkPolAdd(linPoly, plonkPolScale(ZPoly, zCoeff))
+	linPoly = plonkPolAdd(linPoly, plonkPolScale(H0, hCoeff0))
+	linPoly = plonkPolAdd(linPoly, plonkPolScale(H1, hCoeff1))
+	linPoly = plonkPolAdd(linPoly, plonkPolScale(H2, hCoeff2))
+
+	if got := plonkPolEval(linPoly, zeta); !got.Equal(&constLin) {
+		t.Fatalf("buildHonestPlonkProof: linPoly(zeta) = %s != constLin = %s", got.String(), constLin.String())
+	}
+
+	tr.bind(constLin, l, r, o, s1, s2, zu)
+	v := tr.challenge()
+
+	folded := linPoly
+	vPow := v
+	folded = plonkPolAdd(folded, plonkPolScale(aPoly, vPow))
+	vPow.Mul(&vPow, &v)
+	folded = plonkPolAdd(folded, plonkPolScale(bPoly, vPow))
+	vPow.Mul(&vPow, &v)
+	folded = plonkPolAdd(folded, plonkPolScale(cPoly, vPow))
+	vPow.Mul(&vPow, &v)
+	folded = plonkPolAdd(folded, plonkPolScale(S1Poly, vPow))
+	vPow.Mul(&vPow, &v)
+	folded = plonkPolAdd(folded, plonkPolScale(S2Poly, vPow))
+
+	// kzg.Open(folded, zeta, pk).ClaimedValue is folded(zeta) = constLin +
+	// v*l + v^2*r + v^3*o + v^4*s1 + v^5*s2 (the batched opening target E
+	// the verifier reconstructs), not constLin itself - the H it returns is
+	// still the correct quotient commitment, but the proof's own
+	// ClaimedValue field must carry constLin alone, matching how the
+	// verifier folds ClaimedValue back into E via the same v powers.
+	batchedProof, err := kzg.Open(append([]fr.Element{}, folded...), zeta, pk)
+	if err != nil {
+		t.Fatalf("kzg.Open(folded): %v", err)
+	}
+	zShiftedProof, err := kzg.Open(append([]fr.Element{}, ZPoly...), zshift, pk)
+	if err != nil {
+		t.Fatalf("kzg.Open(ZPoly): %v", err)
+	}
+
+	toG1 := func(d kzg.Digest) testG1 { return toTestG1(d) }
+
+	vk := PlonkVerifyingKey{
+		Size:           n,
+		NbPublicInputs: 0,
+		Generator:      generator,
+		CosetShift1:    2,
+		CosetShift2:    3,
+		Ql:             toG1(commit([]fr.Element{Ql})).point(),
+		Qr:             toG1(commit([]fr.Element{Qr})).point(),
+		Qm:             toG1(commit([]fr.Element{Qm})).point(),
+		Qo:             toG1(commit([]fr.Element{Qo})).point(),
+		Qk:             toG1(commit([]fr.Element{Qk})).point(),
+		S1:             toG1(S1Comm).point(),
+		S2:             toG1(S2Comm).point(),
+		S3:             toG1(S3Comm).point(),
+		KZGG2:          sw_bn254.NewG2Affine(srs.Vk.G2[0]),
+		KZGG2X:         sw_bn254.NewG2Affine(srs.Vk.G2[1]),
+	}
+
+	proof := PlonkProof{
+		LRO:       [3]PlonkG1{toG1(aComm).point(), toG1(bComm).point(), toG1(cComm).point()},
+		LROEvals:  [3]frontend.Variable{frVarOf(l), frVarOf(r), frVarOf(o)},
+		PermEvals: [2]frontend.Variable{frVarOf(s1), frVarOf(s2)},
+		Z:         toG1(ZComm).point(),
+		H:         [3]PlonkG1{toG1(H0Comm).point(), toG1(H1Comm).point(), toG1(H2Comm).point()},
+		BatchedProof: PlonkOpeningProof{
+			H:            toG1(batchedProof.H).point(),
+			ClaimedValue: frVarOf(constLin),
+		},
+		ZShiftedOpening: PlonkOpeningProof{
+			H:            toG1(zShiftedProof.H).point(),
+			ClaimedValue: frVarOf(zShiftedProof.ClaimedValue),
+		},
+	}
+
+	return honestPlonkProof{vk: vk, proof: proof, witness: PlonkWitness{Public: nil}}
+}
+
+// frVarOf converts a gnark-crypto fr.Element into a frontend.Variable
+// witness value via its big.Int representation.
+func frVarOf(e fr.Element) frontend.Variable {
+	var b big.Int
+	e.BigInt(&b)
+	return &b
+}
+
+// TestAssertProofAcceptsHonestProofRejectsTampered builds a real,
+// honestly-generated BN254 PLONK proof (genuine selector/permutation/
+// grand-product polynomials, KZG commitments and openings, and the exact
+// Fiat-Shamir challenge order AssertProof itself derives) and checks that
+// AssertProof accepts it, then tampers with a single claimed opening and
+// checks that AssertProof rejects the result. The existing unit tests only
+// exercise linearizationCommitment/vanishingPolyEval/lagrangeEval in
+// isolation, so neither the missing quotient-polynomial fold nor the
+// zCoeff sign bug this package's other fixes address would have been
+// caught by them.
+func TestAssertProofAcceptsHonestProofRejectsTampered(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	honest := buildHonestPlonkProof(t)
+
+	placeholderVK := PlaceholderVerifyingKey(honest.vk)
+	placeholderVK.Ql, placeholderVK.Qr, placeholderVK.Qm = placeholderG1(), placeholderG1(), placeholderG1()
+	placeholderVK.Qo, placeholderVK.Qk = placeholderG1(), placeholderG1()
+	placeholderVK.S1, placeholderVK.S2, placeholderVK.S3 = placeholderG1(), placeholderG1(), placeholderG1()
+
+	placeholderProof := PlonkProof{
+		LRO: [3]PlonkG1{placeholderG1(), placeholderG1(), placeholderG1()},
+		Z:   placeholderG1(),
+		H:   [3]PlonkG1{placeholderG1(), placeholderG1(), placeholderG1()},
+		BatchedProof: PlonkOpeningProof{
+			H: placeholderG1(),
+		},
+		ZShiftedOpening: PlonkOpeningProof{
+			H: placeholderG1(),
+		},
+	}
+
+	tampered := honest
+	// Flip a single claimed wire opening: AssertProof must now reject,
+	// since it no longer matches the opening it was derived from.
+	one := frontend.Variable(big.NewInt(1))
+	tampered.proof.LROEvals[0] = rawAdd(honest.proof.LROEvals[0], one)
+
+	assert.CheckCircuit(&assertProofCircuit{VK: placeholderVK, Proof: placeholderProof, Witness: PlaceholderWitness(0)},
+		test.WithValidAssignment(&assertProofCircuit{VK: honest.vk, Proof: honest.proof, Witness: honest.witness}),
+		test.WithInvalidAssignment(&assertProofCircuit{VK: honest.vk, Proof: tampered.proof, Witness: honest.witness}),
+		test.WithCurves(ecc.BN254),
+	)
+}
+
+func placeholderG1() PlonkG1 {
+	return PlonkG1{X: emulated.ValueOf[emparams.BN254Fp](0), Y: emulated.ValueOf[emparams.BN254Fp](0)}
+}
+
+// rawAdd adds two frontend.Variable big.Int witness values, used to
+// tamper with a single claimed opening without going through a circuit.
+func rawAdd(a, b frontend.Variable) frontend.Variable {
+	av := a.(*big.Int)
+	bv := b.(*big.Int)
+	out := new(big.Int).Add(av, bv)
+	return out
+}
@@ -0,0 +1,266 @@
+package recursion
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+// testCurveAPI is a self-contained CurveAPI built directly on gnark's own
+// emulated BN254 curve/field std-library types, so this package's tests can
+// exercise real (not faked) curve arithmetic without importing the caller's
+// package main (a main package can never be imported, which is exactly why
+// CurveAPI exists as an interface in the first place).
+type testCurveAPI struct {
+	api      frontend.API
+	curveApi *sw_emulated.Curve[emparams.BN254Fp, emparams.BN254Fr]
+	frApi    *emulated.Field[emparams.BN254Fr]
+	fpApi    *emulated.Field[emparams.BN254Fp]
+}
+
+func newTestCurveAPI(api frontend.API) (*testCurveAPI, error) {
+	curveApi, err := sw_emulated.New[emparams.BN254Fp, emparams.BN254Fr](api, sw_emulated.GetCurveParams[emparams.BN254Fp]())
+	if err != nil {
+		return nil, err
+	}
+	frApi, err := emulated.NewField[emparams.BN254Fr](api)
+	if err != nil {
+		return nil, err
+	}
+	fpApi, err := emulated.NewField[emparams.BN254Fp](api)
+	if err != nil {
+		return nil, err
+	}
+	return &testCurveAPI{api: api, curveApi: curveApi, frApi: frApi, fpApi: fpApi}, nil
+}
+
+func (c *testCurveAPI) toFr(scalar frontend.Variable) *emulated.Element[emparams.BN254Fr] {
+	return c.frApi.FromBits(c.api.ToBinary(scalar)...)
+}
+
+func (c *testCurveAPI) BN254ScalarMul(point *sw_emulated.AffinePoint[emparams.BN254Fp], scalar frontend.Variable) *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	return c.curveApi.ScalarMul(point, c.toFr(scalar))
+}
+
+func (c *testCurveAPI) BN254ScalarMulConstant(point [2]big.Int, scalar frontend.Variable) *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	p := &sw_emulated.AffinePoint[emparams.BN254Fp]{
+		X: emulated.ValueOf[emparams.BN254Fp](point[0]),
+		Y: emulated.ValueOf[emparams.BN254Fp](point[1]),
+	}
+	return c.BN254ScalarMul(p, scalar)
+}
+
+func (c *testCurveAPI) BN254AddG1(a, b *sw_emulated.AffinePoint[emparams.BN254Fp]) *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	return c.curveApi.Add(a, b)
+}
+
+func (c *testCurveAPI) BN254ScalarMulAndAddG1(point *sw_emulated.AffinePoint[emparams.BN254Fp], scalar frontend.Variable, b *sw_emulated.AffinePoint[emparams.BN254Fp]) *sw_emulated.AffinePoint[emparams.BN254Fp] {
+	return c.curveApi.Add(c.BN254ScalarMul(point, scalar), b)
+}
+
+func (c *testCurveAPI) BN254FpBits(e *emulated.Element[emparams.BN254Fp]) []frontend.Variable {
+	return c.fpApi.ToBits(e)
+}
+
+func (c *testCurveAPI) BN254KZGPairingCheck(p1 []*sw_emulated.AffinePoint[emparams.BN254Fp], g2 []*sw_bn254.G2Affine) error {
+	pairingApi, err := sw_bn254.NewPairing(c.api)
+	if err != nil {
+		return err
+	}
+	return pairingApi.PairingCheck(p1, g2)
+}
+
+// vanishingPolyEvalCircuit exercises PlonkVerifier.vanishingPolyEval in
+// isolation: Z_H(zeta) = zeta^n - 1 must be zero at every n-th root of
+// unity and nonzero everywhere else.
+type vanishingPolyEvalCircuit struct {
+	Zeta     frontend.Variable
+	Expected frontend.Variable
+	N        uint64
+}
+
+func (c *vanishingPolyEvalCircuit) Define(api frontend.API) error {
+	pv := NewPlonkVerifier(api, nil)
+	api.AssertIsEqual(pv.vanishingPolyEval(c.Zeta, c.N), c.Expected)
+	return nil
+}
+
+func TestVanishingPolyEval(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	const n = 8
+	generator, ok := new(big.Int).SetString(
+		"19540430494807482326159819597004422086093766032135589407132600596362845576832", 10,
+	) // a primitive 8th root of unity mod the BN254 scalar field
+	if !ok {
+		t.Fatal("invalid generator constant")
+	}
+
+	assert.CheckCircuit(&vanishingPolyEvalCircuit{N: n},
+		test.WithValidAssignment(&vanishingPolyEvalCircuit{Zeta: generator, Expected: 0, N: n}),
+		test.WithInvalidAssignment(&vanishingPolyEvalCircuit{Zeta: 2, Expected: 0, N: n}),
+		test.WithCurves(ecc.BN254),
+	)
+}
+
+// lagrangeEvalCircuit exercises PlonkVerifier.lagrangeEval: the Lagrange
+// basis polynomials of an n-element domain must sum to 1 at any point.
+type lagrangeEvalCircuit struct {
+	Zeta      frontend.Variable
+	Generator frontend.Variable
+	N         uint64
+}
+
+func (c *lagrangeEvalCircuit) Define(api frontend.API) error {
+	pv := NewPlonkVerifier(api, nil)
+	zhZeta := pv.vanishingPolyEval(c.Zeta, c.N)
+
+	sum := frontend.Variable(0)
+	for i := uint64(0); i < c.N; i++ {
+		sum = api.Add(sum, pv.lagrangeEval(c.Zeta, zhZeta, c.N, c.Generator, i))
+	}
+	api.AssertIsEqual(sum, 1)
+	return nil
+}
+
+func TestLagrangeEvalSumsToOne(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	const n = 4
+	generator, ok := new(big.Int).SetString(
+		"21888242871839275217838484774961031246007050428528088939761107053157389710902", 10,
+	) // a primitive 4th root of unity mod the BN254 scalar field
+	if !ok {
+		t.Fatal("invalid generator constant")
+	}
+
+	assert.CheckCircuit(&lagrangeEvalCircuit{N: n},
+		test.WithValidAssignment(&lagrangeEvalCircuit{Zeta: 5, Generator: generator, N: n}),
+		test.WithCurves(ecc.BN254),
+	)
+}
+
+// linearizationCommitmentCircuit exercises PlonkVerifier.linearizationCommitment
+// against real BN254 curve arithmetic: Ql*a + Qr*b + Qm*(a*b) + Qo*c + Qk +
+// S3*s3Coeff + Z*zCoeff - H[0]*hCoeff0 - H[1]*hCoeff1 - H[2]*hCoeff2,
+// computed natively via gnark-crypto for comparison.
+type linearizationCommitmentCircuit struct {
+	VK                        PlonkVerifyingKey
+	A, B, C, S3Coeff, ZCoeff  frontend.Variable
+	Z                         PlonkG1
+	H                         [3]PlonkG1
+	HCoeff0, HCoeff1, HCoeff2 frontend.Variable
+	Expected                  PlonkG1
+}
+
+func (c *linearizationCommitmentCircuit) Define(api frontend.API) error {
+	curve, err := newTestCurveAPI(api)
+	if err != nil {
+		return err
+	}
+	pv := NewPlonkVerifier(api, curve)
+	got := pv.linearizationCommitment(c.VK, c.A, c.B, c.C, c.S3Coeff, c.Z, c.ZCoeff, c.H, c.HCoeff0, c.HCoeff1, c.HCoeff2)
+
+	curve.fpApi.AssertIsEqual(&got.X, &c.Expected.X)
+	curve.fpApi.AssertIsEqual(&got.Y, &c.Expected.Y)
+	return nil
+}
+
+func TestLinearizationCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	_, _, g1Gen, _ := bn254.Generators()
+
+	scalarOf := func(v int64) *big.Int { return big.NewInt(v) }
+	pointAt := func(scalar int64) (bn254.G1Affine, testG1) {
+		var p bn254.G1Affine
+		p.ScalarMultiplication(&g1Gen, scalarOf(scalar))
+		return p, toTestG1(p)
+	}
+
+	qlPoint, ql := pointAt(2)
+	qrPoint, qr := pointAt(3)
+	qmPoint, qm := pointAt(5)
+	qoPoint, qo := pointAt(7)
+	qkPoint, qk := pointAt(11)
+	s3Point, s3 := pointAt(13)
+	zPoint, z := pointAt(31)
+	h0Point, h0 := pointAt(41)
+	h1Point, h1 := pointAt(43)
+	h2Point, h2 := pointAt(47)
+
+	a, b, cVal, s3Coeff, zCoeff := int64(17), int64(19), int64(23), int64(29), int64(37)
+	hCoeff0, hCoeff1, hCoeff2 := int64(53), int64(59), int64(61)
+
+	var acc, term bn254.G1Affine
+	acc.ScalarMultiplication(&qlPoint, scalarOf(a))
+	term.ScalarMultiplication(&qrPoint, scalarOf(b))
+	acc.Add(&acc, &term)
+	term.ScalarMultiplication(&qmPoint, scalarOf(a*b))
+	acc.Add(&acc, &term)
+	term.ScalarMultiplication(&qoPoint, scalarOf(cVal))
+	acc.Add(&acc, &term)
+	acc.Add(&acc, &qkPoint)
+	term.ScalarMultiplication(&s3Point, scalarOf(s3Coeff))
+	acc.Add(&acc, &term)
+	term.ScalarMultiplication(&zPoint, scalarOf(zCoeff))
+	acc.Add(&acc, &term)
+	term.ScalarMultiplication(&h0Point, scalarOf(hCoeff0))
+	acc.Add(&acc, &term)
+	term.ScalarMultiplication(&h1Point, scalarOf(hCoeff1))
+	acc.Add(&acc, &term)
+	term.ScalarMultiplication(&h2Point, scalarOf(hCoeff2))
+	acc.Add(&acc, &term)
+
+	valid := &linearizationCommitmentCircuit{
+		VK: PlonkVerifyingKey{
+			Generator:   0,
+			CosetShift1: 0, CosetShift2: 0,
+			Ql: ql.point(), Qr: qr.point(), Qm: qm.point(), Qo: qo.point(), Qk: qk.point(), S3: s3.point(),
+		},
+		A: a, B: b, C: cVal, S3Coeff: s3Coeff, Z: z.point(), ZCoeff: zCoeff,
+		H:       [3]PlonkG1{h0.point(), h1.point(), h2.point()},
+		HCoeff0: hCoeff0, HCoeff1: hCoeff1, HCoeff2: hCoeff2,
+		Expected: toTestG1(acc).point(),
+	}
+
+	placeholderG1 := func() PlonkG1 {
+		return PlonkG1{X: emulated.ValueOf[emparams.BN254Fp](0), Y: emulated.ValueOf[emparams.BN254Fp](0)}
+	}
+
+	assert.CheckCircuit(&linearizationCommitmentCircuit{
+		VK: PlonkVerifyingKey{
+			Ql: placeholderG1(), Qr: placeholderG1(), Qm: placeholderG1(), Qo: placeholderG1(), Qk: placeholderG1(), S3: placeholderG1(),
+		},
+		H:        [3]PlonkG1{placeholderG1(), placeholderG1(), placeholderG1()},
+		Z:        placeholderG1(),
+		Expected: placeholderG1(),
+	},
+		test.WithValidAssignment(valid),
+		test.WithCurves(ecc.BN254),
+	)
+}
+
+// testG1 holds a PlonkG1's coordinates as big.Ints, a thin helper
+// so TestLinearizationCommitment can build witness-ready PlonkG1 values
+// from gnark-crypto bn254.G1Affine points computed natively.
+type testG1 struct{ x, y big.Int }
+
+func (g testG1) point() PlonkG1 {
+	return PlonkG1{X: emulated.ValueOf[emparams.BN254Fp](&g.x), Y: emulated.ValueOf[emparams.BN254Fp](&g.y)}
+}
+
+func toTestG1(p bn254.G1Affine) testG1 {
+	var g testG1
+	p.X.BigInt(&g.x)
+	p.Y.BigInt(&g.y)
+	return g
+}
@@ -5,25 +5,91 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/gnark/std/hash/sha2"
-	"github.com/consensys/gnark/std/math/emulated/emparams"
 	"github.com/consensys/gnark/std/math/uints"
 )
 
-func squeezeChallenge(
-	api frontend.API,
-	absorbing *[]uints.U8,
-	challenges *[]frontend.Variable,
-) error {
-	*absorbing = append(*absorbing, uints.NewU8(0))
+// fiatShamirTranscript abstracts the hash used to derive Fiat-Shamir
+// challenges in getChallengesShPlonkCircuit, so that the expensive
+// bit-oriented SHA-256 transcript can be swapped for a cheaper algebraic
+// hash when the prover side is also under our control.
+type fiatShamirTranscript interface {
+	commonU256(x U256)
+	// commonWord absorbs one proof-derived field-element word. Unlike
+	// commonU256, the word's concrete type (U256, U384, ...) depends on
+	// which Halo2Curve produced it, so it is boxed as `any` and unboxed via
+	// the curve that owns it.
+	commonWord(curve Halo2Curve, word any)
+	commonPoint(curve Halo2Curve, transcript *[]any, commitments *[]any)
+	commonScalar(curve Halo2Curve, transcript *[]any)
+	squeezeChallenge() (frontend.Variable, error)
+}
+
+func newFiatShamirTranscript(api frontend.API, u64Api *uints.BinaryField[uints.U64], u256Api *U256API, config Halo2VerifierConfig) (fiatShamirTranscript, error) {
+	switch config.TranscriptHash {
+	case "", "sha256":
+		return &sha256Transcript{api: api, u64Api: u64Api, u256Api: u256Api}, nil
+	case "poseidon":
+		if config.ProofCurve == "bls12381" {
+			return nil, fmt.Errorf("poseidon transcript is not supported for proof_curve %q: its field elements are wider than this circuit's native scalar field", config.ProofCurve)
+		}
+		mimcApi, err := mimc.NewMiMC(api)
+		if err != nil {
+			return nil, fmt.Errorf("newFiatShamirTranscript: %w", err)
+		}
+		return &poseidonTranscript{api: api, u256Api: u256Api, mimcApi: mimcApi}, nil
+	default:
+		return nil, fmt.Errorf("unsupported transcript_hash %q", config.TranscriptHash)
+	}
+}
+
+// sha256Transcript is the original bit-oriented transcript matching the
+// native Halo2 SHA-256 Fiat-Shamir transcript.
+type sha256Transcript struct {
+	api       frontend.API
+	u64Api    *uints.BinaryField[uints.U64]
+	u256Api   *U256API
+	absorbing []uints.U8
+}
+
+func (t *sha256Transcript) commonU256(x U256) {
+	// Append bytes in BE
+	for i := 3; i >= 0; i-- {
+		for j := 7; j >= 0; j-- {
+			t.absorbing = append(t.absorbing, x[i][j])
+		}
+	}
+}
 
-	sha2Api, err := sha2.New(api)
+func (t *sha256Transcript) commonWord(curve Halo2Curve, word any) {
+	t.absorbing = append(t.absorbing, curve.WordBytesBE(word)...)
+}
+
+func (t *sha256Transcript) commonPoint(curve Halo2Curve, transcript *[]any, commitments *[]any) {
+	xWord, yWord := (*transcript)[0], (*transcript)[1]
+	p := curve.AssertOnCurve(xWord, yWord)
+	*commitments = append(*commitments, p)
+
+	t.commonWord(curve, xWord)
+	t.commonWord(curve, yWord)
+	*transcript = (*transcript)[2:]
+}
+
+func (t *sha256Transcript) commonScalar(curve Halo2Curve, transcript *[]any) {
+	t.commonWord(curve, (*transcript)[0])
+	*transcript = (*transcript)[1:]
+}
+
+func (t *sha256Transcript) squeezeChallenge() (frontend.Variable, error) {
+	t.absorbing = append(t.absorbing, uints.NewU8(0))
+
+	sha2Api, err := sha2.New(t.api)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	sha2Api.Write(*absorbing)
+	sha2Api.Write(t.absorbing)
 	res := sha2Api.Sum()
 	if len(res) != 32 {
 		panic("sha2 returned value not 32 bytes")
@@ -34,84 +100,100 @@ func squeezeChallenge(
 	sum := res[31].Val
 	for i := 1; i < 32; i++ {
 		base = base.Lsh(base, 8)
-		sum = api.Add(sum, api.Mul(res[31-i].Val, base))
+		sum = t.api.Add(sum, t.api.Mul(res[31-i].Val, base))
 	}
 
-	*absorbing = res
-	*challenges = append(*challenges, sum)
+	t.absorbing = res
+	return sum, nil
+}
 
-	return nil
+// poseidonTranscript replaces the byte-level SHA-256 sponge with an
+// algebraic hash (MiMC) absorbing native scalars directly, avoiding the
+// bit-decomposition cost that dominates squeezeChallenge under SHA-256.
+// It only matches a proof whose prover-side transcript was built with the
+// same algebraic hash (see TranscriptHash in Halo2VerifierConfig).
+type poseidonTranscript struct {
+	api     frontend.API
+	u256Api *U256API
+	mimcApi mimc.MiMC
 }
 
-func commonU256(
-	api frontend.API,
-	absorbing *[]uints.U8,
-	x U256,
-) {
-	// Append bytes in BE
-	for i := 3; i >= 0; i-- {
-		for j := 7; j >= 0; j-- {
-			*absorbing = append(*absorbing, x[i][j])
-		}
-	}
+func (t *poseidonTranscript) commonU256(x U256) {
+	bits := t.u256Api.ToBits(x)
+	t.mimcApi.Write(t.api.FromBinary(bits...))
 }
 
-func commonScalar(
-	api frontend.API,
-	absorbing *[]uints.U8,
-	transcript *[]U256,
-) {
-	commonU256(api, absorbing, (*transcript)[0])
-	*transcript = (*transcript)[1:]
+func (t *poseidonTranscript) commonWord(curve Halo2Curve, word any) {
+	t.mimcApi.Write(curve.WordToValue(word))
 }
 
-func commonPoint(
-	api frontend.API,
-	bn254Api *BN254API,
-	absorbing *[]uints.U8,
-	transcript *[]U256,
-	commitments *[]*sw_emulated.AffinePoint[emparams.BN254Fp],
-) {
-	p := bn254Api.AssertOnCurve((*transcript)[0], (*transcript)[1])
+func (t *poseidonTranscript) commonPoint(curve Halo2Curve, transcript *[]any, commitments *[]any) {
+	xWord, yWord := (*transcript)[0], (*transcript)[1]
+	p := curve.AssertOnCurve(xWord, yWord)
 	*commitments = append(*commitments, p)
 
-	commonU256(api, absorbing, (*transcript)[0])
-	commonU256(api, absorbing, (*transcript)[1])
+	t.commonWord(curve, xWord)
+	t.commonWord(curve, yWord)
 	*transcript = (*transcript)[2:]
 }
 
+func (t *poseidonTranscript) commonScalar(curve Halo2Curve, transcript *[]any) {
+	t.commonWord(curve, (*transcript)[0])
+	*transcript = (*transcript)[1:]
+}
+
+func (t *poseidonTranscript) squeezeChallenge() (frontend.Variable, error) {
+	sum := t.mimcApi.Sum()
+	t.mimcApi.Reset()
+	t.mimcApi.Write(sum)
+	return sum, nil
+}
+
 // Return challenges and commitments
 func (halo2Api *Halo2VerifierAPI) getChallengesShPlonkCircuit(
-	instanceCommitments []*sw_emulated.AffinePoint[emparams.BN254Fp],
-	transcript []U256,
-) ([]frontend.Variable, []*sw_emulated.AffinePoint[emparams.BN254Fp], []frontend.Variable, error) {
-	var absorbing []uints.U8
+	instanceCommitments []any,
+	transcript []any,
+) ([]frontend.Variable, []any, []frontend.Variable, []any, []any, error) {
 	var challenges []frontend.Variable
-	var commitments []*sw_emulated.AffinePoint[emparams.BN254Fp]
+	var commitments []any
 	var evals []frontend.Variable
 
+	t, err := halo2Api.newTranscript()
+	if err != nil {
+		return challenges, commitments, evals, nil, nil, err
+	}
+
 	challengeInitScalar, succeed := new(big.Int).SetString(halo2Api.config.ChallengeInitScalar, 10)
 	if !succeed {
-		return challenges, commitments, evals, fmt.Errorf("invalid ChallengeInitScalar %s", halo2Api.config.ChallengeInitScalar)
-	}
-	{
-		bytes := make([]byte, 32)
-		bytes = challengeInitScalar.FillBytes(bytes)
-		for i := 0; i < 32; i++ {
-			absorbing = append(absorbing, uints.NewU8(bytes[i]))
-		}
+		return challenges, commitments, evals, nil, nil, fmt.Errorf("invalid ChallengeInitScalar %s", halo2Api.config.ChallengeInitScalar)
 	}
+	t.commonU256(NewU256(*challengeInitScalar))
 
 	for i := range instanceCommitments {
-		commonU256(halo2Api.api, &absorbing, halo2Api.bn254Api.BN254FpToU256(&(*instanceCommitments[i]).X))
-		commonU256(halo2Api.api, &absorbing, halo2Api.bn254Api.BN254FpToU256(&(*instanceCommitments[i]).Y))
+		xWord, yWord := halo2Api.curve.PointXY(instanceCommitments[i])
+		t.commonWord(halo2Api.curve, xWord)
+		t.commonWord(halo2Api.curve, yWord)
+	}
+
+	// Committed (Pedersen/BSB22-style) columns each contribute one
+	// commitment and one knowledge-of-opening proof, absorbed right after
+	// the instance commitments and before the usual SHPlonk opening
+	// sequence.
+	pedersenCommitments, pedersenPoks, err := halo2Api.absorbPedersenColumns(t, &transcript)
+	if err != nil {
+		return challenges, commitments, evals, nil, nil, err
 	}
 
 	opSeq := [][3]uint32{
-		{halo2Api.config.NbAdvices, 1, 0},                                           // theta
-		{halo2Api.config.NbLookupsM, 2, 0},                                       // beta, gamma
+		{halo2Api.config.NbAdvices, 1, 0},                                             // theta
+		{halo2Api.config.NbLookupsM, 2, 0},                                            // beta, gamma
 		{halo2Api.config.NbPermutationGroups + halo2Api.config.NbLookupsZs + 1, 1, 0}, // y
-		{halo2Api.config.Degree, 1, halo2Api.config.NbEvals},                        // x
+		{halo2Api.config.Degree, 1, halo2Api.config.NbEvals},                          // x
+		// The trailing NbShiftedEvals entries of the opening sequence above
+		// (the permutation/lookup grand-product commitments) are opened a
+		// second time here, at zeta*Omega, appended to evals past NbEvals so
+		// verify can split the zeta-only openings from the shifted ones.
+		{0, 0, halo2Api.config.NbShiftedEvals},
 		{0, 2, 0}, // y, v in multiopen
 		{1, 1, 0}, // u in multiopen
 		{1, 0, 0}, //
@@ -119,21 +201,56 @@ func (halo2Api *Halo2VerifierAPI) getChallengesShPlonkCircuit(
 
 	for i := range opSeq {
 		for j := uint32(0); j < opSeq[i][0]; j++ {
-			commonPoint(halo2Api.api, halo2Api.bn254Api, &absorbing, &transcript, &commitments)
+			t.commonPoint(halo2Api.curve, &transcript, &commitments)
 		}
 
 		for j := uint32(0); j < opSeq[i][1]; j++ {
-			err := squeezeChallenge(halo2Api.api, &absorbing, &challenges)
+			challenge, err := t.squeezeChallenge()
 			if err != nil {
-				return challenges, commitments, evals, err
+				return challenges, commitments, evals, nil, nil, err
 			}
+			challenges = append(challenges, challenge)
 		}
 
 		for j := uint32(0); j < opSeq[i][2]; j++ {
-			evals = append(evals, halo2Api.u256Api.ToValue(transcript[0]))
-			commonScalar(halo2Api.api, &absorbing, &transcript)
+			evals = append(evals, halo2Api.curve.WordToValue(transcript[0]))
+			t.commonScalar(halo2Api.curve, &transcript)
+		}
+	}
+
+	return challenges, commitments, evals, pedersenCommitments, pedersenPoks, nil
+}
+
+// absorbPedersenColumns pops one (commitment, knowledge-of-opening proof)
+// point pair per column configured in PedersenBasesG1 off the front of the
+// transcript, asserting each point on curve and absorbing both into t, same
+// as commonPoint does for the SHPlonk opening commitments.
+func (halo2Api *Halo2VerifierAPI) absorbPedersenColumns(t fiatShamirTranscript, transcript *[]any) ([]any, []any, error) {
+	n := len(halo2Api.config.PedersenBasesG1)
+	if n == 0 {
+		return nil, nil, nil
+	}
+
+	commitments := make([]any, n)
+	poks := make([]any, n)
+
+	for i := 0; i < n; i++ {
+		if len(*transcript) < 4 {
+			return nil, nil, fmt.Errorf("absorbPedersenColumns: transcript too short for column %d", i)
 		}
+
+		cx, cy := (*transcript)[0], (*transcript)[1]
+		commitments[i] = halo2Api.curve.AssertOnCurve(cx, cy)
+		t.commonWord(halo2Api.curve, cx)
+		t.commonWord(halo2Api.curve, cy)
+
+		px, py := (*transcript)[2], (*transcript)[3]
+		poks[i] = halo2Api.curve.AssertOnCurve(px, py)
+		t.commonWord(halo2Api.curve, px)
+		t.commonWord(halo2Api.curve, py)
+
+		*transcript = (*transcript)[4:]
 	}
 
-	return challenges, commitments, evals, nil
+	return commitments, poks, nil
 }
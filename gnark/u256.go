@@ -72,6 +72,60 @@ func NewU256(
 	return res
 }
 
+// U384 is the BLS12-381 counterpart of U256: BLS12-381's ~381-bit Fp
+// elements don't fit in 256 bits, so the Halo2Curve BLS12-381
+// implementation parses proof transcript words into this wider type
+// instead.
+type U384 [6]uints.U64
+
+type U384API struct {
+	api    frontend.API
+	u64Api *uints.BinaryField[uints.U64]
+}
+
+func NewU384API(api frontend.API, u64Api *uints.BinaryField[uints.U64]) *U384API {
+	return &U384API{
+		api:    api,
+		u64Api: u64Api,
+	}
+}
+
+func (u384Api *U384API) ToBits(
+	x U384,
+) []frontend.Variable {
+	bits := []frontend.Variable{}
+	for i := range x {
+		for j := range x[i] {
+			bits = append(bits, u384Api.api.ToBinary(x[i][j].Val, 8)...)
+		}
+	}
+	return bits
+}
+
+func (u384Api *U384API) FromBits(
+	bits []frontend.Variable,
+) U384 {
+	res := U384{}
+	for i := range res {
+		res[i] = U64FromBits(u384Api.api, u384Api.u64Api, bits[i*64:(i+1)*64])
+	}
+	return res
+}
+
+func NewU384(
+	x big.Int,
+) U384 {
+	bytes := make([]byte, 48)
+	bytes = x.FillBytes(bytes)
+
+	res := U384{}
+	for i := range res {
+		res[i] = uints.NewU64(binary.LittleEndian.Uint64(bytes[i*8 : (i+1)*8]))
+	}
+
+	return res
+}
+
 /*
 func ToU256Hint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 	bytes := make([]byte, 32)